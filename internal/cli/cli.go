@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"markup/internal/core"
+)
+
+// 退出码
+const (
+	ExitOK    = 0 // 成功
+	ExitError = 1 // 读写文件等运行时错误
+	ExitUsage = 2 // 命令行参数错误
+)
+
+// command 子命令
+type command struct {
+	name    string                                // 命令名
+	summary string                                // 简要说明
+	run     func(ctx *context, args []string) int // 执行函数
+}
+
+// context 命令执行上下文
+type context struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// commands 所有子命令
+var commands = []command{
+	{name: "render", summary: "将 Markdown 渲染为 HTML 页面", run: runRender},
+	{name: "lint", summary: "检查 Markdown 文件中的常见问题", run: runLint},
+}
+
+// Run 执行命令行，返回进程退出码
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	ctx := &context{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	if len(args) == 0 {
+		printUsage(stderr)
+		return ExitUsage
+	}
+
+	if isHelp(args[0]) {
+		printUsage(stdout)
+		return ExitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(ctx, args[1:])
+		}
+	}
+
+	fmt.Fprintf(stderr, "markup: 未知命令 %q\n\n", args[0])
+	printUsage(stderr)
+	return ExitUsage
+}
+
+// IsCommand 判断参数是否为子命令或帮助选项，用于区分命令行模式和用图形界面打开文件
+func IsCommand(arg string) bool {
+	if isHelp(arg) {
+		return true
+	}
+	for _, cmd := range commands {
+		if cmd.name == arg {
+			return true
+		}
+	}
+	return false
+}
+
+// isHelp 判断参数是否为帮助命令或选项
+func isHelp(arg string) bool {
+	switch arg {
+	case "help", "-h", "-help", "--help":
+		return true
+	}
+	return false
+}
+
+// printUsage 输出帮助信息
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "用法: markup [命令] [参数]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "不带参数运行时启动图形界面，参数为文件或文件夹路径时用图形界面打开它。可用命令:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "使用 \"markup <命令> -h\" 查看命令的参数。")
+}
+
+// parseInterspersed 解析参数，允许选项出现在位置参数之后（如 render in.md -o out.html）
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// readInput 读取输入，路径为空或 "-" 时读取标准输入
+func (ctx *context) readInput(path string) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(ctx.stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// writeOutput 写入输出，路径为空或 "-" 时写入标准输出
+func (ctx *context) writeOutput(path string, data []byte) error {
+	if path == "" || path == "-" {
+		_, err := ctx.stdout.Write(data)
+		return err
+	}
+	return core.WriteFileAtomic(path, data, false)
+}
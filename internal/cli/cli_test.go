@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// run 用内存中的标准输入输出执行命令行
+func run(args []string, stdin string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = Run(args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func TestParseInterspersed(t *testing.T) {
+	tests := []struct {
+		args       []string
+		positional []string
+		output     string
+		toc        bool
+	}{
+		{nil, nil, "", false},
+		{[]string{"a.md"}, []string{"a.md"}, "", false},
+		{[]string{"-o", "out.html", "a.md"}, []string{"a.md"}, "out.html", false},
+		{[]string{"a.md", "-o", "out.html", "--toc"}, []string{"a.md"}, "out.html", true},
+		{[]string{"a.md", "--toc", "b.md"}, []string{"a.md", "b.md"}, "", true},
+		{[]string{"--", "-o"}, []string{"-o"}, "", false}, // -- 之后都是位置参数
+		{[]string{"-"}, []string{"-"}, "", false},
+	}
+
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		output := fs.String("o", "", "")
+		toc := fs.Bool("toc", false, "")
+
+		positional, err := parseInterspersed(fs, tt.args)
+		if err != nil {
+			t.Errorf("parseInterspersed(%q) 出错：%v", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(positional, tt.positional) || *output != tt.output || *toc != tt.toc {
+			t.Errorf("parseInterspersed(%q) = %q, -o %q, --toc %v，应为 %q, -o %q, --toc %v",
+				tt.args, positional, *output, *toc, tt.positional, tt.output, tt.toc)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if _, err := parseInterspersed(fs, []string{"a.md", "--unknown"}); err == nil {
+		t.Error("未知选项应出错")
+	}
+}
+
+func TestRunExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "a.md")
+	if err := os.WriteFile(input, []byte("# 标题\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout string // 标准输出中应包含的内容
+		wantStderr string // 标准错误中应包含的内容
+	}{
+		{"没有参数", nil, ExitUsage, "", "用法"},
+		{"帮助", []string{"help"}, ExitOK, "用法", ""},
+		{"帮助选项", []string{"--help"}, ExitOK, "用法", ""},
+		{"未知命令", []string{"build"}, ExitUsage, "", "未知命令"},
+		{"命令帮助", []string{"render", "-h"}, ExitOK, "", "用法: markup render"},
+		{"未知选项", []string{"render", "--unknown"}, ExitUsage, "", "-unknown"},
+		{"多个输入文件", []string{"render", input, input}, ExitUsage, "", "只能指定一个输入文件"},
+		{"输入文件不存在", []string{"render", filepath.Join(dir, "missing.md")}, ExitError, "", "读取输入失败"},
+		{"渲染文件", []string{"render", input}, ExitOK, "<h1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := run(tt.args, "")
+			if code != tt.want {
+				t.Errorf("退出码为 %d，应为 %d（stderr：%s）", code, tt.want, stderr)
+			}
+			if !strings.Contains(stdout, tt.wantStdout) {
+				t.Errorf("标准输出 %q 中没有 %q", stdout, tt.wantStdout)
+			}
+			if !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("标准错误 %q 中没有 %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRenderStdin(t *testing.T) {
+	code, stdout, stderr := run([]string{"render", "--title", "页面", "--toc"}, "# 一\n\n## 二\n")
+	if code != ExitOK {
+		t.Fatalf("退出码为 %d，应为 0（stderr：%s）", code, stderr)
+	}
+	for _, want := range []string{"<title>页面</title>", `<h1 id="一">`, `href="#二"`} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("输出中没有 %s", want)
+		}
+	}
+}
+
+func TestRenderOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "a.md")
+	if err := os.WriteFile(input, []byte("# 标题\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output := filepath.Join(dir, "a.html")
+
+	// 选项可以出现在输入文件之后
+	code, stdout, stderr := run([]string{"render", input, "-o", output}, "")
+	if code != ExitOK {
+		t.Fatalf("退出码为 %d，应为 0（stderr：%s）", code, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("写入文件时标准输出应为空，实际为 %q", stdout)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<h1") {
+		t.Errorf("输出文件中没有标题：%s", data)
+	}
+
+	// -o - 写入标准输出
+	code, stdout, _ = run([]string{"render", "-o", "-", input}, "")
+	if code != ExitOK || !strings.Contains(stdout, "<h1") {
+		t.Errorf("-o - 时退出码为 %d，输出为 %q", code, stdout)
+	}
+}
+
+func TestRenderOutputError(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "missing", "out.html")
+
+	code, _, stderr := run([]string{"render", "-o", output}, "# 标题\n")
+	if code != ExitError {
+		t.Errorf("退出码为 %d，应为 %d", code, ExitError)
+	}
+	// 报告用户指定的输出路径，而不是写入时使用的临时文件
+	if !strings.Contains(stderr, output) || strings.Contains(stderr, ".tmp-") {
+		t.Errorf("错误信息为 %q，应包含输出路径 %s", stderr, output)
+	}
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"markup/internal/lint"
+)
+
+// fileDiagnostic 带文件名的诊断，用于 JSON 输出
+type fileDiagnostic struct {
+	File string `json:"file"`
+	lint.Diagnostic
+}
+
+// runLint 执行 lint 命令：markup lint [文件...] [--format text|json] [--config 配置文件]
+func runLint(ctx *context, args []string) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(ctx.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(ctx.stderr, "用法: markup lint [文件...] [--format text|json] [--config 配置文件]")
+		fmt.Fprintln(ctx.stderr, "")
+		fmt.Fprintln(ctx.stderr, "未指定文件时从标准输入读取。存在错误级别的问题时退出码为 1。")
+		fmt.Fprintf(ctx.stderr, "未指定配置文件时从每个文件所在目录向上查找 %s（标准输入从当前目录查找）。\n", lint.ConfigFileName)
+		fmt.Fprintln(ctx.stderr, "")
+		fmt.Fprintln(ctx.stderr, "规则:")
+		for _, rule := range lint.Rules() {
+			fmt.Fprintf(ctx.stderr, "  %-22s %-8s %s\n", rule.ID, rule.DefaultSeverity, rule.Description)
+		}
+		fmt.Fprintln(ctx.stderr, "")
+		fs.PrintDefaults()
+	}
+
+	format := fs.String("format", "text", "输出格式：text 或 json")
+	configPath := fs.String("config", "", "配置文件路径")
+
+	files, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(ctx.stderr, "markup lint: 不支持的输出格式 %q\n", *format)
+		return ExitUsage
+	}
+
+	// 指定了配置文件时所有文件都使用它，否则与编辑器相同，从每个文件所在目录向上查找
+	linters := newLinterCache(*configPath)
+	if *configPath != "" {
+		if _, err := linters.forDir(""); err != nil {
+			fmt.Fprintf(ctx.stderr, "markup lint: 加载配置失败: %v\n", err)
+			return ExitError
+		}
+	}
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	exitCode := ExitOK
+	results := make([]fileDiagnostic, 0)
+	for _, file := range files {
+		content, err := ctx.readInput(file)
+		if err != nil {
+			fmt.Fprintf(ctx.stderr, "markup lint: 读取文件失败: %v\n", err)
+			exitCode = ExitError
+			continue
+		}
+
+		name, dir := file, filepath.Dir(file)
+		if name == "-" {
+			name, dir = "<stdin>", "."
+		}
+
+		linter, err := linters.forDir(dir)
+		if err != nil {
+			fmt.Fprintf(ctx.stderr, "markup lint: %s: 加载配置失败: %v\n", name, err)
+			exitCode = ExitError
+			continue
+		}
+
+		diagnostics := linter.Lint(string(content))
+		if lint.HasErrors(diagnostics) {
+			exitCode = ExitError
+		}
+		for _, d := range diagnostics {
+			results = append(results, fileDiagnostic{File: name, Diagnostic: d})
+		}
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(ctx.stdout)
+		encoder.SetIndent("", "  ")
+		// 输出不会嵌入网页，保持 <stdin> 等文件名原样
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(results); err != nil {
+			fmt.Fprintf(ctx.stderr, "markup lint: 写入输出失败: %v\n", err)
+			return ExitError
+		}
+	} else {
+		for _, result := range results {
+			fmt.Fprintf(ctx.stdout, "%s:%s\n", result.File, result.Diagnostic)
+		}
+	}
+
+	return exitCode
+}
+
+// linterCache 按目录缓存检查器，同一目录下的文件只查找和加载一次配置
+type linterCache struct {
+	configPath string                  // 命令行指定的配置文件，为空时按目录查找
+	byDir      map[string]*lint.Linter // 目录对应的检查器
+	byConfig   map[string]*lint.Linter // 配置文件对应的检查器，多个目录共用同一配置时只加载一次
+}
+
+// newLinterCache 创建检查器缓存
+func newLinterCache(configPath string) *linterCache {
+	return &linterCache{
+		configPath: configPath,
+		byDir:      make(map[string]*lint.Linter),
+		byConfig:   make(map[string]*lint.Linter),
+	}
+}
+
+// forDir 获取检查目录中文件使用的检查器，找不到配置文件时使用默认配置
+func (c *linterCache) forDir(dir string) (*lint.Linter, error) {
+	if linter, ok := c.byDir[dir]; ok {
+		return linter, nil
+	}
+
+	path := c.configPath
+	if path == "" {
+		path = lint.FindConfig(dir)
+	}
+	linter, ok := c.byConfig[path]
+	if !ok {
+		config := lint.DefaultConfig()
+		if path != "" {
+			var err error
+			if config, err = lint.LoadConfig(path); err != nil {
+				return nil, err
+			}
+		}
+		linter = lint.NewLinter(config)
+		c.byConfig[path] = linter
+	}
+
+	c.byDir[dir] = linter
+	return linter, nil
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles 在 dir 中创建测试文件，键为相对路径
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLintExitCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  int
+	}{
+		{"没有问题", "# 标题\n\n正文\n", nil, ExitOK},
+		{"只有警告", "# 一\n\n### 三\n", nil, ExitOK},
+		{"只有提示", "# 标题\n\n正文 \n", nil, ExitOK},
+		{"有错误", "#标题\n", nil, ExitError},
+		{"未闭合的代码块", "# 标题\n\n```\n代码\n", nil, ExitError},
+		{"不支持的格式", "# 标题\n", []string{"--format", "xml"}, ExitUsage},
+		{"未知选项", "# 标题\n", []string{"--fix"}, ExitUsage},
+		{"配置文件不存在", "# 标题\n", []string{"--config", filepath.Join(t.TempDir(), "missing.json")}, ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := run(append([]string{"lint"}, tt.args...), tt.stdin)
+			if code != tt.want {
+				t.Errorf("退出码为 %d，应为 %d（stderr：%s）", code, tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestLintTextOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.md": "#标题\n"})
+	path := filepath.Join(dir, "a.md")
+
+	code, stdout, _ := run([]string{"lint", path}, "")
+	if code != ExitError {
+		t.Errorf("退出码为 %d，应为 %d", code, ExitError)
+	}
+	want := path + ":1:2: error [heading-space] "
+	if !strings.HasPrefix(stdout, want) {
+		t.Errorf("输出为 %q，应以 %q 开头", stdout, want)
+	}
+
+	// 读取失败的文件不影响其他文件
+	code, stdout, stderr := run([]string{"lint", filepath.Join(dir, "missing.md"), path}, "")
+	if code != ExitError || !strings.Contains(stderr, "读取文件失败") || !strings.HasPrefix(stdout, want) {
+		t.Errorf("退出码为 %d，输出为 %q，错误为 %q", code, stdout, stderr)
+	}
+}
+
+func TestLintJSONOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.md": "# 一\n\n### 三\n",
+		"b.md": "# 标题\n",
+	})
+
+	code, stdout, stderr := run([]string{"lint", "--format", "json", filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")}, "")
+	if code != ExitOK {
+		t.Errorf("只有警告时退出码为 %d，应为 0（stderr：%s）", code, stderr)
+	}
+
+	var results []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("输出不是有效的 JSON：%v\n%s", err, stdout)
+	}
+	want := []map[string]any{{
+		"file":     filepath.Join(dir, "a.md"),
+		"rule":     "heading-increment",
+		"severity": "warning",
+		"line":     float64(3),
+		"column":   float64(1),
+	}}
+	for _, result := range results {
+		delete(result, "message")
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("JSON 输出为 %v，应为 %v", results, want)
+	}
+
+	// 没有问题时输出空数组而不是 null
+	code, stdout, _ = run([]string{"lint", "--format", "json"}, "# 标题\n")
+	if code != ExitOK || strings.TrimSpace(stdout) != "[]" {
+		t.Errorf("没有问题时退出码为 %d，输出为 %q，应为 []", code, stdout)
+	}
+
+	// 标准输入的文件名
+	_, stdout, _ = run([]string{"lint", "--format", "json"}, "#标题\n")
+	if !strings.Contains(stdout, `"file": "<stdin>"`) {
+		t.Errorf("标准输入的结果中没有文件名 <stdin>：%s", stdout)
+	}
+}
+
+func TestLintConfigPerFile(t *testing.T) {
+	// 每个文件使用自己所在目录（或上级目录）中的配置
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		".markuplint.json":        `{"rules": {"heading-space": "warning"}}`,
+		"docs/a.md":               "#标题\n",
+		"strict/.markuplint.json": `{"rules": {"heading-space": "error"}}`,
+		"strict/b.md":             "#标题\n",
+		"broken/.markuplint.json": `{"rules": {"no-such-rule": "off"}}`,
+		"broken/c.md":             "# 标题\n",
+	})
+	a := filepath.Join(dir, "docs", "a.md")
+	b := filepath.Join(dir, "strict", "b.md")
+	c := filepath.Join(dir, "broken", "c.md")
+
+	code, stdout, _ := run([]string{"lint", a}, "")
+	if code != ExitOK || !strings.Contains(stdout, "warning [heading-space]") {
+		t.Errorf("上级目录的配置没有生效：退出码 %d，输出 %q", code, stdout)
+	}
+
+	code, stdout, _ = run([]string{"lint", a, b}, "")
+	if code != ExitError {
+		t.Errorf("退出码为 %d，应为 %d", code, ExitError)
+	}
+	if !strings.Contains(stdout, a+":1:2: warning") || !strings.Contains(stdout, b+":1:2: error") {
+		t.Errorf("两个文件应分别使用自己目录中的配置，输出为 %q", stdout)
+	}
+
+	// 配置无效的目录报告错误，其他文件照常检查
+	code, stdout, stderr := run([]string{"lint", c, a}, "")
+	if code != ExitError || !strings.Contains(stderr, "未知的规则") || !strings.Contains(stdout, a+":1:2: warning") {
+		t.Errorf("退出码为 %d，输出为 %q，错误为 %q", code, stdout, stderr)
+	}
+
+	// --config 对所有文件生效
+	config := filepath.Join(dir, "strict", ".markuplint.json")
+	code, stdout, _ = run([]string{"lint", "--config", config, a}, "")
+	if code != ExitError || !strings.Contains(stdout, a+":1:2: error") {
+		t.Errorf("--config 没有生效：退出码 %d，输出 %q", code, stdout)
+	}
+}
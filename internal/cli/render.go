@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	"markup/internal/markdown"
+)
+
+// runRender 执行 render 命令：markup render [in.md] [-o out.html] [--toc] [--title 标题]
+func runRender(ctx *context, args []string) int {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(ctx.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(ctx.stderr, "用法: markup render [输入文件] [-o 输出文件] [--toc] [--title 标题] [--self-contained]")
+		fmt.Fprintln(ctx.stderr, "")
+		fmt.Fprintln(ctx.stderr, "未指定输入文件时从标准输入读取，未指定输出文件时写入标准输出。")
+		fs.PrintDefaults()
+	}
+
+	output := fs.String("o", "", "输出文件路径（默认标准输出）")
+	toc := fs.Bool("toc", false, "在正文前插入目录")
+	title := fs.String("title", "", "页面标题（默认使用第一个一级标题）")
+	selfContained := fs.Bool("self-contained", false, "将本地图片内嵌为 data URI")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK
+		}
+		return ExitUsage
+	}
+	if len(positional) > 1 {
+		fmt.Fprintln(ctx.stderr, "markup render: 只能指定一个输入文件")
+		return ExitUsage
+	}
+
+	input := ""
+	if len(positional) == 1 {
+		input = positional[0]
+	}
+
+	content, err := ctx.readInput(input)
+	if err != nil {
+		fmt.Fprintf(ctx.stderr, "markup render: 读取输入失败: %v\n", err)
+		return ExitError
+	}
+
+	// 相对图片路径以输入文件所在目录为基准
+	baseDir := "."
+	if input != "" && input != "-" {
+		baseDir = filepath.Dir(input)
+	}
+
+	renderer := markdown.NewRenderer()
+	result := renderer.ExportHTML(string(content), markdown.ExportOptions{
+		Title:         *title,
+		IncludeTOC:    *toc,
+		SelfContained: *selfContained,
+		BaseDir:       baseDir,
+	})
+
+	for _, missing := range result.MissingImages {
+		fmt.Fprintf(ctx.stderr, "markup render: 警告: 无法读取图片 %s\n", missing)
+	}
+	for _, skipped := range result.SkippedImages {
+		fmt.Fprintf(ctx.stderr, "markup render: 警告: %s 不在输入文件所在目录下或不是图片，没有内嵌\n", skipped)
+	}
+
+	if err := ctx.writeOutput(*output, []byte(result.HTML)); err != nil {
+		fmt.Fprintf(ctx.stderr, "markup render: 写入输出失败: %v\n", err)
+		return ExitError
+	}
+
+	return ExitOK
+}
@@ -0,0 +1,147 @@
+package core
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// WriteFileAtomic 原子地写入文件：先写入同目录下的临时文件并同步到磁盘，
+// 再重命名覆盖目标文件，写入过程中崩溃不会留下写了一半的文件。
+// 目标文件已存在时保留其权限和所有者，新文件与 os.WriteFile 相同以 0666 创建并受 umask 影响；
+// backup 为 true 时把旧内容保存为 .bak 文件
+func WriteFileAtomic(path string, data []byte, backup bool) error {
+	// 跟随符号链接，替换链接指向的文件而不是链接本身
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	// 覆盖已有文件时临时文件先只对自己可读写，写完后再设置为原文件的权限
+	perm := fs.FileMode(0666)
+	var mode fs.FileMode
+	info, err := os.Stat(path)
+	exists := err == nil
+	if exists {
+		perm = 0600
+		mode = info.Mode() & (fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := createTemp(dir, "."+filepath.Base(path)+".tmp-", perm)
+	if err != nil {
+		return tempFileError(path, err)
+	}
+	tmpPath := tmp.Name()
+
+	// 出错时清理临时文件
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return tempFileError(path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return tempFileError(path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return tempFileError(path, err)
+	}
+	if exists {
+		if err := os.Chmod(tmpPath, mode); err != nil {
+			return tempFileError(path, err)
+		}
+		// 没有权限修改所有者时（如保存他人的文件）保持当前用户
+		preserveOwner(tmpPath, info)
+	}
+
+	if backup && exists {
+		if err := copyFile(path, path+".bak", mode); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return tempFileError(path, err)
+	}
+	succeeded = true
+
+	// 同步目录，确保重命名本身也已落盘
+	syncDir(dir)
+	return nil
+}
+
+// tempFileError 把临时文件操作的错误改为报告目标文件路径，临时文件名（如 .a.md.tmp-123）对用户没有意义
+func tempFileError(path string, err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return &fs.PathError{Op: pathErr.Op, Path: path, Err: pathErr.Err}
+	}
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) {
+		return &fs.PathError{Op: linkErr.Op, Path: path, Err: linkErr.Err}
+	}
+	return err
+}
+
+// createTemp 与 os.CreateTemp 相同，在 dir 中创建以 prefix 开头的临时文件，但使用指定的权限创建
+// （os.CreateTemp 总是使用 0600，新文件无法得到受 umask 影响的默认权限）
+func createTemp(dir, prefix string, perm fs.FileMode) (*os.File, error) {
+	for try := 0; try < 10000; try++ {
+		name := filepath.Join(dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		return f, err
+	}
+	return nil, &fs.PathError{Op: "createtemp", Path: filepath.Join(dir, prefix+"*"), Err: fs.ErrExist}
+}
+
+// copyFile 复制文件内容
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	// 创建时的权限受 umask 影响，已存在的文件也保持原来的权限，需要明确设置
+	if err := out.Chmod(mode); err != nil {
+		out.Close()
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// syncDir 同步目录元数据，部分平台不支持时忽略错误
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	d.Sync()
+}
@@ -0,0 +1,283 @@
+package core
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// readFile 读取文件内容，出错时终止测试
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// writeFile 写入测试文件，出错时终止测试
+func writeFile(t *testing.T, path, content string, mode fs.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile 创建文件时受 umask 影响，这里明确设置权限
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// dirNames 列出目录中的文件名，用于检查是否留下了临时文件
+func dirNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  string // 原有内容，为空表示文件不存在
+		backup    bool
+		wantFiles []string
+	}{
+		{"新建文件", "", false, []string{"a.md"}},
+		{"新建文件时不备份", "", true, []string{"a.md"}},
+		{"覆盖文件", "旧内容", false, []string{"a.md"}},
+		{"覆盖文件并备份", "旧内容", true, []string{"a.md", "a.md.bak"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "a.md")
+			if tt.existing != "" {
+				writeFile(t, path, tt.existing, 0644)
+			}
+
+			if err := WriteFileAtomic(path, []byte("新内容"), tt.backup); err != nil {
+				t.Fatalf("WriteFileAtomic() 出错：%v", err)
+			}
+			if got := readFile(t, path); got != "新内容" {
+				t.Errorf("文件内容为 %q，应为 %q", got, "新内容")
+			}
+			if names := dirNames(t, dir); !equalStrings(names, tt.wantFiles) {
+				t.Errorf("目录中的文件为 %q，应为 %q", names, tt.wantFiles)
+			}
+			if tt.backup && tt.existing != "" {
+				if got := readFile(t, path+".bak"); got != tt.existing {
+					t.Errorf("备份内容为 %q，应为 %q", got, tt.existing)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomicReplacesBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.md")
+	writeFile(t, path, "第一版", 0644)
+
+	for _, content := range []string{"第二版", "第三版"} {
+		if err := WriteFileAtomic(path, []byte(content), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 备份总是保存上一次写入前的内容
+	if got := readFile(t, path+".bak"); got != "第二版" {
+		t.Errorf("备份内容为 %q，应为 %q", got, "第二版")
+	}
+}
+
+func TestWriteFileAtomicMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows 不支持 Unix 权限位")
+	}
+
+	tests := []struct {
+		name string
+		mode fs.FileMode
+	}{
+		{"只有所有者可读写", 0600},
+		{"可执行", 0755},
+		{"所有人可读写", 0666},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "a.md")
+			writeFile(t, path, "旧内容", tt.mode)
+
+			if err := WriteFileAtomic(path, []byte("新内容"), true); err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range []string{path, path + ".bak"} {
+				info, err := os.Stat(p)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := info.Mode().Perm(); got != tt.mode {
+					t.Errorf("%s 的权限为 %v，应为 %v", filepath.Base(p), got, tt.mode)
+				}
+			}
+		})
+	}
+
+	// 新建的文件与 os.WriteFile 创建的文件权限相同（0666 受 umask 影响）
+	dir := t.TempDir()
+	reference := filepath.Join(dir, "reference.md")
+	if err := os.WriteFile(reference, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	refInfo, err := os.Stat(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "new.md")
+	if err := WriteFileAtomic(path, []byte("内容"), false); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != refInfo.Mode().Perm() {
+		t.Errorf("新建文件的权限为 %v（%v），应为 %v", info.Mode().Perm(), err, refInfo.Mode().Perm())
+	}
+}
+
+func TestWriteFileAtomicSymlink(t *testing.T) {
+	root := t.TempDir()
+	realDir := filepath.Join(root, "real")
+	linkDir := filepath.Join(root, "links")
+	for _, dir := range []string{realDir, linkDir} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	target := filepath.Join(realDir, "a.md")
+	writeFile(t, target, "旧内容", 0600)
+
+	tests := []struct {
+		name   string
+		target string // 链接内容
+	}{
+		{"绝对路径的链接", target},
+		{"相对路径的链接", filepath.Join("..", "real", "a.md")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := filepath.Join(linkDir, "link.md")
+			os.Remove(link)
+			if err := os.Symlink(tt.target, link); err != nil {
+				t.Skipf("无法创建符号链接：%v", err)
+			}
+
+			if err := WriteFileAtomic(link, []byte(tt.name), true); err != nil {
+				t.Fatal(err)
+			}
+
+			// 链接保持不变，内容写入链接指向的文件，备份也保存在目标文件旁
+			info, err := os.Lstat(link)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode()&fs.ModeSymlink == 0 {
+				t.Fatal("符号链接被替换为普通文件")
+			}
+			if got := readFile(t, target); got != tt.name {
+				t.Errorf("目标文件内容为 %q，应为 %q", got, tt.name)
+			}
+			if _, err := os.Stat(target + ".bak"); err != nil {
+				t.Errorf("目标文件旁没有备份：%v", err)
+			}
+			if names := dirNames(t, linkDir); !equalStrings(names, []string{"link.md"}) {
+				t.Errorf("链接所在目录中的文件为 %q", names)
+			}
+			if runtime.GOOS != "windows" {
+				if info, err := os.Stat(target); err != nil || info.Mode().Perm() != 0600 {
+					t.Errorf("目标文件的权限为 %v（%v），应为 0600", info.Mode().Perm(), err)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomicFailure(t *testing.T) {
+	t.Run("目录不存在", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "a.md")
+		err := WriteFileAtomic(path, []byte("内容"), false)
+		if err == nil {
+			t.Fatal("目录不存在时应出错")
+		}
+		// 错误中报告目标路径而不是临时文件名
+		if msg := err.Error(); !strings.Contains(msg, path) || strings.Contains(msg, ".tmp-") {
+			t.Errorf("错误信息为 %q，应包含 %s 而不包含临时文件名", msg, path)
+		}
+	})
+
+	t.Run("无法替换目标时清理临时文件", func(t *testing.T) {
+		dir := t.TempDir()
+		// 目标是非空目录，重命名会失败
+		path := filepath.Join(dir, "a.md")
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeFile(t, filepath.Join(path, "child"), "", 0644)
+
+		err := WriteFileAtomic(path, []byte("内容"), false)
+		if err == nil {
+			t.Fatal("目标为非空目录时应出错")
+		}
+		if msg := err.Error(); strings.Contains(msg, ".tmp-") {
+			t.Errorf("错误信息 %q 中包含临时文件名", msg)
+		}
+		if names := dirNames(t, dir); !equalStrings(names, []string{"a.md"}) {
+			t.Errorf("出错后目录中的文件为 %q，临时文件应被删除", names)
+		}
+	})
+
+	t.Run("无法备份时不修改原文件", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "a.md")
+		writeFile(t, path, "旧内容", 0644)
+		// .bak 位置是目录，无法写入备份
+		if err := os.Mkdir(path+".bak", 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := WriteFileAtomic(path, []byte("新内容"), true); err == nil {
+			t.Fatal("无法备份时应出错")
+		}
+		if got := readFile(t, path); got != "旧内容" {
+			t.Errorf("文件内容为 %q，应保持 %q", got, "旧内容")
+		}
+		if names := dirNames(t, dir); !equalStrings(names, []string{"a.md", "a.md.bak"}) {
+			t.Errorf("出错后目录中的文件为 %q，临时文件应被删除", names)
+		}
+	})
+}
+
+// equalStrings 判断两个字符串列表是否相同
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
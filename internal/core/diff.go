@@ -0,0 +1,295 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffOp 差异操作类型
+type DiffOp int
+
+const (
+	DiffEqual  DiffOp = iota // 相同
+	DiffDelete               // 仅在旧文本中
+	DiffInsert               // 仅在新文本中
+)
+
+// DiffLine 差异中的一行
+type DiffLine struct {
+	Op      DiffOp // 操作类型
+	Text    string // 行内容
+	OldLine int    // 在旧文本中的行号（从1开始，插入行为 0）
+	NewLine int    // 在新文本中的行号（从1开始，删除行为 0）
+}
+
+// maxDiffCost 比较时搜索的最大编辑距离（约为两段文本之间差异行数的一半），
+// 超过时不再查找相同的行，把剩余部分整体显示为删除和插入，避免大文件完全改写时耗时过长
+const maxDiffCost = 1000
+
+// DiffLines 逐行比较两段文本
+// 使用 Myers 差异算法的线性空间版本，内存占用与行数成正比，时间与行数和差异行数的乘积成正比
+func DiffLines(oldText, newText string) []DiffLine {
+	d := &lineDiff{
+		a:       strings.Split(oldText, "\n"),
+		b:       strings.Split(newText, "\n"),
+		oldLine: 1,
+		newLine: 1,
+	}
+
+	// 相同的行编号相同，比较时只需比较编号
+	ids := make(map[string]int)
+	lineIDs := func(lines []string) []int {
+		result := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+			result[i] = id
+		}
+		return result
+	}
+	d.diff(lineIDs(d.a), lineIDs(d.b))
+	return d.result
+}
+
+// lineDiff 逐行比较时按顺序收集差异行
+type lineDiff struct {
+	a, b    []string // 旧文本和新文本的行
+	result  []DiffLine
+	oldLine int // 下一个旧文本行的行号
+	newLine int // 下一个新文本行的行号
+}
+
+// equal 添加 n 个相同的行
+func (d *lineDiff) equal(n int) {
+	for ; n > 0; n-- {
+		d.result = append(d.result, DiffLine{Op: DiffEqual, Text: d.a[d.oldLine-1], OldLine: d.oldLine, NewLine: d.newLine})
+		d.oldLine++
+		d.newLine++
+	}
+}
+
+// delete 添加 n 个仅在旧文本中的行
+func (d *lineDiff) delete(n int) {
+	for ; n > 0; n-- {
+		d.result = append(d.result, DiffLine{Op: DiffDelete, Text: d.a[d.oldLine-1], OldLine: d.oldLine})
+		d.oldLine++
+	}
+}
+
+// insert 添加 n 个仅在新文本中的行
+func (d *lineDiff) insert(n int) {
+	for ; n > 0; n-- {
+		d.result = append(d.result, DiffLine{Op: DiffInsert, Text: d.b[d.newLine-1], NewLine: d.newLine})
+		d.newLine++
+	}
+}
+
+// diff 比较行编号 a 与 b：去掉相同的开头和结尾，中间部分在最短编辑路径的中点处分成两半分别比较
+func (d *lineDiff) diff(a, b []int) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	d.equal(prefix)
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+	switch {
+	case len(midA) == 0:
+		d.insert(len(midB))
+	case len(midB) == 0:
+		d.delete(len(midA))
+	default:
+		x, y, ok := middleSnake(midA, midB)
+		if ok {
+			d.diff(midA[:x], midB[:y])
+			d.diff(midA[x:], midB[y:])
+		} else {
+			// 没有相同的行或差异过大
+			d.delete(len(midA))
+			d.insert(len(midB))
+		}
+	}
+	d.equal(suffix)
+}
+
+// middleSnake 同时从两端搜索 a 到 b 的最短编辑路径，返回两个方向的路径相遇的位置
+// a 与 b 都不能为空，且开头和结尾的行不同；没有相同的行或编辑距离超过 maxDiffCost 时 ok 为 false
+func middleSnake(a, b []int) (x, y int, ok bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	// forward[offset+k] 为正向搜索时对角线 k（x-y=k）上到达的最远 x，
+	// backward 为反向搜索（从两段文本的末尾开始）时的对应值，-1 表示尚未到达
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	delta := n - m
+	// 行数之差为奇数时两个方向的路径在正向搜索中相遇，否则在反向搜索中相遇
+	front := delta%2 != 0
+	// 超出文本范围的对角线不再搜索
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for d := 0; d < min(maxD, maxDiffCost); d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			i := offset + k
+			var x1 int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x1 = forward[i+1]
+			} else {
+				x1 = forward[i-1] + 1
+			}
+			y1 := x1 - k
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			forward[i] = x1
+			switch {
+			case x1 > n:
+				fEnd += 2
+			case y1 > m:
+				fStart += 2
+			case front:
+				j := offset + delta - k
+				if j >= 0 && j < len(backward) && backward[j] != -1 && x1 >= n-backward[j] {
+					return x1, y1, true
+				}
+			}
+		}
+
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			i := offset + k
+			var x2 int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x2 = backward[i+1]
+			} else {
+				x2 = backward[i-1] + 1
+			}
+			y2 := x2 - k
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2++
+				y2++
+			}
+			backward[i] = x2
+			switch {
+			case x2 > n:
+				bEnd += 2
+			case y2 > m:
+				bStart += 2
+			case !front:
+				j := offset + delta - k
+				if j >= 0 && j < len(forward) && forward[j] != -1 {
+					x1 := forward[j]
+					y1 := x1 - (j - offset)
+					if x1 >= n-x2 {
+						return x1, y1, true
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// UnifiedDiff 生成统一格式（diff -u）的差异文本，context 为变更前后保留的上下文行数
+// 两段文本相同时返回空字符串
+func UnifiedDiff(oldName, newName, oldText, newText string, context int) string {
+	lines := DiffLines(oldText, newText)
+
+	// 找出需要输出的行：变更行及其上下文
+	include := make([]bool, len(lines))
+	changed := false
+	for i, line := range lines {
+		if line.Op == DiffEqual {
+			continue
+		}
+		changed = true
+		for k := i - context; k <= i+context; k++ {
+			if k >= 0 && k < len(lines) {
+				include[k] = true
+			}
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(lines); {
+		if !include[start] {
+			start++
+			continue
+		}
+		end := start
+		for end < len(lines) && include[end] {
+			end++
+		}
+
+		// 计算块头中的起始行号和行数
+		oldStart, newStart, oldCount, newCount := 0, 0, 0, 0
+		for _, line := range lines[start:end] {
+			if line.Op != DiffInsert {
+				if oldStart == 0 {
+					oldStart = line.OldLine
+				}
+				oldCount++
+			}
+			if line.Op != DiffDelete {
+				if newStart == 0 {
+					newStart = line.NewLine
+				}
+				newCount++
+			}
+		}
+		// 块中没有某一侧的行时，起始行号为该侧在块之前的最后一行
+		if oldCount == 0 {
+			oldStart = lastLineBefore(lines, start, func(l DiffLine) int { return l.OldLine })
+		}
+		if newCount == 0 {
+			newStart = lastLineBefore(lines, start, func(l DiffLine) int { return l.NewLine })
+		}
+		fmt.Fprintf(&buf, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+		for _, line := range lines[start:end] {
+			switch line.Op {
+			case DiffEqual:
+				buf.WriteString(" ")
+			case DiffDelete:
+				buf.WriteString("-")
+			case DiffInsert:
+				buf.WriteString("+")
+			}
+			buf.WriteString(line.Text)
+			buf.WriteString("\n")
+		}
+		start = end
+	}
+
+	return buf.String()
+}
+
+// lastLineBefore 获取 index 之前最后一个有效的行号，没有时返回 0
+func lastLineBefore(lines []DiffLine, index int, lineNumber func(DiffLine) int) int {
+	for k := index - 1; k >= 0; k-- {
+		if n := lineNumber(lines[k]); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
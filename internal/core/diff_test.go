@@ -0,0 +1,156 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// applyDiff 从差异中还原旧文本和新文本，并检查行号是否连续
+func applyDiff(t *testing.T, lines []DiffLine) (string, string, int) {
+	t.Helper()
+	var oldLines, newLines []string
+	changes := 0
+	for _, line := range lines {
+		if line.Op != DiffInsert {
+			oldLines = append(oldLines, line.Text)
+			if line.OldLine != len(oldLines) {
+				t.Fatalf("旧文本行号为 %d，应为 %d", line.OldLine, len(oldLines))
+			}
+		}
+		if line.Op != DiffDelete {
+			newLines = append(newLines, line.Text)
+			if line.NewLine != len(newLines) {
+				t.Fatalf("新文本行号为 %d，应为 %d", line.NewLine, len(newLines))
+			}
+		}
+		if line.Op != DiffEqual {
+			changes++
+		}
+	}
+	return strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"), changes
+}
+
+// lcsLength 用动态规划计算最长公共子序列的长度，作为最少变更行数的参照
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		ops      string // 每行的操作：= 相同，- 删除，+ 插入
+	}{
+		{"相同", "a\nb", "a\nb", "=="},
+		{"空文本", "", "", "="},
+		{"插入", "a\nc", "a\nb\nc", "=+="},
+		{"删除", "a\nb\nc", "a\nc", "=-="},
+		{"修改", "a\nb\nc", "a\nx\nc", "=-+="},
+		{"完全不同", "a\nb", "x\ny", "--++"},
+		{"重复的行", "a\na\na\na", "a\na", "==--"},
+		{"中间相同", "x\nm\ny", "p\nm\nq", "-+=-+"},
+	}
+	symbols := map[DiffOp]byte{DiffEqual: '=', DiffDelete: '-', DiffInsert: '+'}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := DiffLines(tt.old, tt.new)
+			var ops strings.Builder
+			for _, line := range lines {
+				ops.WriteByte(symbols[line.Op])
+			}
+			if ops.String() != tt.ops {
+				t.Errorf("DiffLines(%q, %q) = %s，应为 %s", tt.old, tt.new, ops.String(), tt.ops)
+			}
+			oldText, newText, _ := applyDiff(t, lines)
+			if oldText != tt.old || newText != tt.new {
+				t.Errorf("无法从差异还原文本：%q, %q", oldText, newText)
+			}
+		})
+	}
+}
+
+func TestDiffLinesMinimal(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomText := func() string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	for i := 0; i < 500; i++ {
+		old, new := randomText(), randomText()
+		oldText, newText, changes := applyDiff(t, DiffLines(old, new))
+		if oldText != old || newText != new {
+			t.Fatalf("无法从差异还原文本：%q -> %q", old, new)
+		}
+		a, b := strings.Split(old, "\n"), strings.Split(new, "\n")
+		if want := len(a) + len(b) - 2*lcsLength(a, b); changes != want {
+			t.Fatalf("DiffLines(%q, %q) 有 %d 行变更，最少为 %d 行", old, new, changes, want)
+		}
+	}
+}
+
+func TestDiffLinesLargeFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		changed func(i int) bool // 第 i 行是否修改
+		changes int              // 变更行数
+	}{
+		// 差异不大时结果仍然是最少的变更
+		{"少量修改", func(i int) bool { return i%100 == 0 }, 2 * 80},
+		// 完全改写时除了开头相同的行，其余整体显示为删除和插入
+		{"完全改写", func(i int) bool { return i%100 != 0 }, 2 * 7999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var oldLines, newLines []string
+			for i := 0; i < 8000; i++ {
+				if tt.changed(i) {
+					oldLines = append(oldLines, fmt.Sprintf("old %d", i))
+					newLines = append(newLines, fmt.Sprintf("new %d", i))
+				} else {
+					oldLines = append(oldLines, fmt.Sprintf("same %d", i))
+					newLines = append(newLines, fmt.Sprintf("same %d", i))
+				}
+			}
+			old, new := strings.Join(oldLines, "\n"), strings.Join(newLines, "\n")
+			oldText, newText, changes := applyDiff(t, DiffLines(old, new))
+			if oldText != old || newText != new {
+				t.Fatal("无法从差异还原文本")
+			}
+			if changes != tt.changes {
+				t.Errorf("有 %d 行变更，应为 %d 行", changes, tt.changes)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng"
+	new := "a\nb\nc\nD\ne\nf\ng"
+	want := "--- old\n+++ new\n@@ -3,3 +3,3 @@\n c\n-d\n+D\n e\n"
+	if got := UnifiedDiff("old", "new", old, new, 1); got != want {
+		t.Errorf("UnifiedDiff() = %q，应为 %q", got, want)
+	}
+	if got := UnifiedDiff("old", "new", old, old, 1); got != "" {
+		t.Errorf("相同文本的 UnifiedDiff() = %q，应为空", got)
+	}
+}
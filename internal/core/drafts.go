@@ -0,0 +1,131 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Draft 自动保存的草稿
+type Draft struct {
+	ID       string    `json:"id"`        // 草稿ID
+	FilePath string    `json:"file_path"` // 对应的文件路径，未命名文档为空
+	Content  string    `json:"content"`   // 草稿内容
+	SavedAt  time.Time `json:"saved_at"`  // 保存时间
+}
+
+// DraftStore 草稿存储，每份草稿保存为恢复目录中的一个 JSON 文件
+type DraftStore struct {
+	dir string // 恢复目录
+}
+
+// NewDraftStore 创建草稿存储，dir 为空时使用用户配置目录下的 recovery 目录
+func NewDraftStore(dir string) (*DraftStore, error) {
+	if dir == "" {
+		configDir, err := ConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(configDir, "recovery")
+	}
+	return &DraftStore{dir: dir}, nil
+}
+
+// NewDraftID 生成新的草稿ID
+func NewDraftID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return time.Now().Format("20060102-150405-") + hex.EncodeToString(buf)
+}
+
+// Save 保存草稿（原子写入，避免留下不完整的草稿）
+func (s *DraftStore) Save(draft *Draft) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(s.path(draft.ID), data, false)
+}
+
+// Remove 删除草稿，草稿不存在时不报错
+func (s *DraftStore) Remove(id string) error {
+	err := os.Remove(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List 列出所有草稿，按保存时间从新到旧排序，无法解析的文件会被忽略
+func (s *DraftStore) List() ([]*Draft, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var drafts []*Draft
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			continue
+		}
+		draft := &Draft{}
+		if err := json.Unmarshal(data, draft); err != nil {
+			continue
+		}
+		// ID 必须与文件名一致，避免删除草稿时访问恢复目录以外的文件
+		if draft.ID == "" || draft.ID != strings.TrimSuffix(name, ".json") {
+			continue
+		}
+		drafts = append(drafts, draft)
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].SavedAt.After(drafts[j].SavedAt)
+	})
+	return drafts, nil
+}
+
+// FindByFile 查找文件最新的草稿，没有草稿或 filePath 为空（未命名文档）时返回 nil
+func (s *DraftStore) FindByFile(filePath string) (*Draft, error) {
+	if filePath == "" {
+		return nil, nil
+	}
+	drafts, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, draft := range drafts {
+		if draft.FilePath != "" && filepath.Clean(draft.FilePath) == filepath.Clean(filePath) {
+			return draft, nil
+		}
+	}
+	return nil, nil
+}
+
+// path 获取草稿文件路径
+func (s *DraftStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
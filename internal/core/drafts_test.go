@@ -0,0 +1,176 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// draftIDs 获取草稿的ID
+func draftIDs(drafts []*Draft) []string {
+	var ids []string
+	for _, draft := range drafts {
+		ids = append(ids, draft.ID)
+	}
+	return ids
+}
+
+func TestDraftStoreSaveAndList(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "recovery")
+	store, err := NewDraftStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 恢复目录不存在时没有草稿
+	if drafts, err := store.List(); err != nil || len(drafts) != 0 {
+		t.Fatalf("List() = %d 份草稿，出错：%v，应没有草稿", len(drafts), err)
+	}
+
+	now := time.Now()
+	saved := []*Draft{
+		{ID: "old", FilePath: "/docs/a.md", Content: "旧", SavedAt: now.Add(-time.Hour)},
+		{ID: "new", FilePath: "", Content: "未命名", SavedAt: now},
+		{ID: "mid", FilePath: "/docs/b.md", Content: "中", SavedAt: now.Add(-time.Minute)},
+	}
+	for _, draft := range saved {
+		if err := store.Save(draft); err != nil {
+			t.Fatalf("Save(%s) 出错：%v", draft.ID, err)
+		}
+	}
+
+	drafts, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := draftIDs(drafts); !equalStrings(got, []string{"new", "mid", "old"}) {
+		t.Fatalf("List() = %q，应按保存时间从新到旧排序", got)
+	}
+	if drafts[0].FilePath != "" || drafts[0].Content != "未命名" {
+		t.Errorf("未命名草稿读回为 %+v", drafts[0])
+	}
+	if drafts[1].FilePath != "/docs/b.md" || drafts[1].Content != "中" || !drafts[1].SavedAt.Equal(saved[2].SavedAt) {
+		t.Errorf("文件草稿读回为 %+v，应为 %+v", drafts[1], saved[2])
+	}
+
+	// 再次保存同一ID时覆盖原草稿
+	if err := store.Save(&Draft{ID: "old", FilePath: "/docs/a.md", Content: "更新", SavedAt: now.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	drafts, err = store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := draftIDs(drafts); !equalStrings(got, []string{"old", "new", "mid"}) || drafts[0].Content != "更新" {
+		t.Errorf("覆盖后 List() = %q，第一份内容为 %q", got, drafts[0].Content)
+	}
+}
+
+func TestDraftStoreListIgnoresInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDraftStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(&Draft{ID: "valid", Content: "正文", SavedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "broken.json"), "{", 0600)
+	writeFile(t, filepath.Join(dir, "renamed.json"), `{"id": "../other", "content": "正文"}`, 0600)
+	writeFile(t, filepath.Join(dir, "empty.json"), `{"content": "正文"}`, 0600)
+	writeFile(t, filepath.Join(dir, ".tmp-1.json"), `{"id": ".tmp-1"}`, 0600)
+	writeFile(t, filepath.Join(dir, "notes.txt"), `{"id": "notes"}`, 0600)
+	if err := os.Mkdir(filepath.Join(dir, "sub.json"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	drafts, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := draftIDs(drafts); !equalStrings(got, []string{"valid"}) {
+		t.Errorf("List() = %q，应只包含有效的草稿", got)
+	}
+}
+
+func TestDraftStoreRemove(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDraftStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := store.Save(&Draft{ID: id, Content: id, SavedAt: time.Now()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.Remove("a"); err != nil {
+		t.Fatalf("Remove() 出错：%v", err)
+	}
+	if err := store.Remove("a"); err != nil {
+		t.Errorf("草稿不存在时 Remove() 出错：%v", err)
+	}
+	if names := dirNames(t, dir); !equalStrings(names, []string{"b.json"}) {
+		t.Errorf("删除后目录中的文件为 %q，应为 [b.json]", names)
+	}
+}
+
+func TestDraftStoreFindByFile(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDraftStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, draft := range []*Draft{
+		{ID: "untitled", FilePath: "", Content: "未命名", SavedAt: now},
+		{ID: "a-old", FilePath: "/docs/a.md", Content: "旧", SavedAt: now.Add(-time.Hour)},
+		{ID: "a-new", FilePath: "/docs/a.md", Content: "新", SavedAt: now.Add(-time.Minute)},
+		{ID: "b", FilePath: "/docs/b.md", Content: "b", SavedAt: now},
+	} {
+		if err := store.Save(draft); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		want     string // 草稿ID，为空表示没有草稿
+	}{
+		{"同一文件有多份草稿时取最新的", "/docs/a.md", "a-new"},
+		{"路径不规范", "/docs/./sub/../a.md", "a-new"},
+		{"其他文件", "/docs/b.md", "b"},
+		{"没有草稿的文件", "/docs/c.md", ""},
+		{"未命名文档不沿用草稿", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			draft, err := store.FindByFile(tt.filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if draft != nil {
+				got = draft.ID
+			}
+			if got != tt.want {
+				t.Errorf("FindByFile(%q) = %q，应为 %q", tt.filePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDraftID(t *testing.T) {
+	a, b := NewDraftID(), NewDraftID()
+	if a == "" || a == b {
+		t.Errorf("NewDraftID() 生成了 %q 和 %q，应为不同的非空ID", a, b)
+	}
+	if filepath.Base(a) != a {
+		t.Errorf("草稿ID %q 不能包含路径分隔符", a)
+	}
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrFileChanged 文件内容与预期不一致（在预览或替换之后被修改过），为避免覆盖他人的修改而跳过
+var ErrFileChanged = errors.New("文件已被修改")
+
+// maxJournals 最多保留的替换记录数，记录中保存了文件的完整内容，超出时删除最早的记录
+const maxJournals = 20
+
+// FileEdit 对一个文件的整体修改
+type FileEdit struct {
+	Path   string `json:"path"`   // 文件路径
+	Before string `json:"before"` // 修改前的内容
+	After  string `json:"after"`  // 修改后的内容
+}
+
+// ReplaceJournal 一次工作区替换的记录，用于撤销整个替换
+type ReplaceJournal struct {
+	ID          string     `json:"id"`          // 记录ID
+	Description string     `json:"description"` // 替换的说明（如查找和替换的内容）
+	CreatedAt   time.Time  `json:"created_at"`  // 替换时间
+	Files       []FileEdit `json:"files"`       // 写入的文件（写入前保存的记录中包含计划写入的所有文件）
+}
+
+// JournalStore 替换记录存储，每条记录保存为记录目录中的一个 JSON 文件
+type JournalStore struct {
+	dir string // 记录目录
+}
+
+// NewJournalStore 创建替换记录存储，dir 为空时使用用户配置目录下的 journal 目录
+func NewJournalStore(dir string) (*JournalStore, error) {
+	if dir == "" {
+		configDir, err := ConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(configDir, "journal")
+	}
+	return &JournalStore{dir: dir}, nil
+}
+
+// Save 保存替换记录（原子写入）
+func (s *JournalStore) Save(journal *ReplaceJournal) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(journal)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFileAtomic(s.path(journal.ID), data, false); err != nil {
+		return err
+	}
+	s.prune(maxJournals)
+	return nil
+}
+
+// prune 只保留最近的 keep 条记录，删除失败的记录留到下次再删
+func (s *JournalStore) prune(keep int) {
+	journals, err := s.List()
+	if err != nil {
+		return
+	}
+	for i := keep; i < len(journals); i++ {
+		s.Remove(journals[i].ID)
+	}
+}
+
+// Remove 删除替换记录，记录不存在时不报错
+func (s *JournalStore) Remove(id string) error {
+	err := os.Remove(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Latest 获取最近一次替换的记录，没有记录时返回 nil
+func (s *JournalStore) Latest() (*ReplaceJournal, error) {
+	journals, err := s.List()
+	if err != nil || len(journals) == 0 {
+		return nil, err
+	}
+	return journals[0], nil
+}
+
+// List 列出所有替换记录，按替换时间从新到旧排序，无法解析的文件会被忽略
+func (s *JournalStore) List() ([]*ReplaceJournal, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var journals []*ReplaceJournal
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			continue
+		}
+		journal := &ReplaceJournal{}
+		if err := json.Unmarshal(data, journal); err != nil {
+			continue
+		}
+		// ID 必须与文件名一致，避免删除记录时访问记录目录以外的文件
+		if journal.ID == "" || journal.ID != strings.TrimSuffix(name, ".json") {
+			continue
+		}
+		journals = append(journals, journal)
+	}
+	sort.Slice(journals, func(i, j int) bool {
+		return journals[i].CreatedAt.After(journals[j].CreatedAt)
+	})
+	return journals, nil
+}
+
+// RemoveFiles 从替换记录中去掉已恢复的文件，记录中没有文件时删除记录
+func (s *JournalStore) RemoveFiles(journal *ReplaceJournal, reverted []FileEdit) error {
+	done := make(map[string]bool)
+	for _, edit := range reverted {
+		done[edit.Path] = true
+	}
+	var remaining []FileEdit
+	for _, edit := range journal.Files {
+		if !done[edit.Path] {
+			remaining = append(remaining, edit)
+		}
+	}
+
+	journal.Files = remaining
+	if len(remaining) == 0 {
+		return s.Remove(journal.ID)
+	}
+	return s.Save(journal)
+}
+
+// path 获取替换记录文件路径
+func (s *JournalStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// ApplyEdits 依次把修改写入文件，写入方式与 AppState.SaveFile 相同
+// 磁盘上的内容与 Before 不一致的文件会被跳过；返回成功写入的修改以及所有失败的原因
+func ApplyEdits(edits []FileEdit, keepBackup bool) ([]FileEdit, error) {
+	return writeEdits(edits, keepBackup, func(edit FileEdit) (string, string) {
+		return edit.Before, edit.After
+	})
+}
+
+// RevertEdits 把文件恢复为修改前的内容，可以只恢复替换记录中的部分文件
+// 替换之后又被修改过的文件会被跳过；返回成功恢复的修改以及所有失败的原因
+func RevertEdits(edits []FileEdit, keepBackup bool) ([]FileEdit, error) {
+	return writeEdits(edits, keepBackup, func(edit FileEdit) (string, string) {
+		return edit.After, edit.Before
+	})
+}
+
+// writeEdits 检查每个文件的内容是否为 expected，一致时写入 content
+func writeEdits(edits []FileEdit, keepBackup bool, contents func(FileEdit) (expected, content string)) ([]FileEdit, error) {
+	state := NewAppState()
+	state.SetKeepBackup(keepBackup)
+
+	var written []FileEdit
+	var errs []error
+	for _, edit := range edits {
+		expected, content := contents(edit)
+
+		current, err := state.LoadFile(edit.Path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// 已经是目标内容（如写入过程中崩溃而未写入的文件）时不需要再写入
+		if current == content {
+			written = append(written, edit)
+			continue
+		}
+		if current != expected {
+			errs = append(errs, fmt.Errorf("%s: %w", edit.Path, ErrFileChanged))
+			continue
+		}
+
+		if err := state.SaveFile(edit.Path, content); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		written = append(written, edit)
+	}
+	return written, errors.Join(errs...)
+}
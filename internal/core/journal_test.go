@@ -0,0 +1,230 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// journalFiles 创建测试文件，返回对这些文件的修改
+func journalFiles(t *testing.T, dir string, contents ...string) []FileEdit {
+	t.Helper()
+	var edits []FileEdit
+	for i, content := range contents {
+		path := filepath.Join(dir, fmt.Sprintf("%d.md", i))
+		writeFile(t, path, content, 0644)
+		edits = append(edits, FileEdit{Path: path, Before: content, After: content + "（已替换）"})
+	}
+	return edits
+}
+
+func TestApplyAndRevertEdits(t *testing.T) {
+	dir := t.TempDir()
+	edits := journalFiles(t, dir, "一", "二")
+
+	applied, err := ApplyEdits(edits, false)
+	if err != nil || len(applied) != 2 {
+		t.Fatalf("ApplyEdits() 写入 %d 个文件，出错：%v，应写入 2 个", len(applied), err)
+	}
+	for _, edit := range edits {
+		if got := readFile(t, edit.Path); got != edit.After {
+			t.Errorf("替换后 %s 的内容为 %q，应为 %q", filepath.Base(edit.Path), got, edit.After)
+		}
+	}
+
+	reverted, err := RevertEdits(edits, false)
+	if err != nil || len(reverted) != 2 {
+		t.Fatalf("RevertEdits() 恢复 %d 个文件，出错：%v，应恢复 2 个", len(reverted), err)
+	}
+	for _, edit := range edits {
+		if got := readFile(t, edit.Path); got != edit.Before {
+			t.Errorf("恢复后 %s 的内容为 %q，应为 %q", filepath.Base(edit.Path), got, edit.Before)
+		}
+	}
+	if names := dirNames(t, dir); !equalStrings(names, []string{"0.md", "1.md"}) {
+		t.Errorf("不保留备份时目录中的文件为 %q", names)
+	}
+}
+
+func TestApplyEditsKeepBackup(t *testing.T) {
+	dir := t.TempDir()
+	edits := journalFiles(t, dir, "一")
+
+	if _, err := ApplyEdits(edits, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, edits[0].Path+".bak"); got != edits[0].Before {
+		t.Errorf("备份内容为 %q，应为 %q", got, edits[0].Before)
+	}
+}
+
+func TestEditsSkipChangedFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply bool   // true 测试替换，false 测试恢复
+		disk  string // 写入前磁盘上第一个文件的内容
+	}{
+		{"替换前被修改", true, "别人的修改"},
+		{"恢复前被修改", false, "别人的修改"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			edits := journalFiles(t, dir, "一", "二")
+			if !tt.apply {
+				if _, err := ApplyEdits(edits, false); err != nil {
+					t.Fatal(err)
+				}
+			}
+			writeFile(t, edits[0].Path, tt.disk, 0644)
+
+			write := RevertEdits
+			if tt.apply {
+				write = ApplyEdits
+			}
+			written, err := write(edits, false)
+
+			if !errors.Is(err, ErrFileChanged) {
+				t.Errorf("错误为 %v，应为 ErrFileChanged", err)
+			}
+			if len(written) != 1 || written[0].Path != edits[1].Path {
+				t.Errorf("写入了 %+v，应只写入第二个文件", written)
+			}
+			// 被修改过的文件保持原样
+			if got := readFile(t, edits[0].Path); got != tt.disk {
+				t.Errorf("被修改过的文件内容为 %q，应保持 %q", got, tt.disk)
+			}
+		})
+	}
+}
+
+func TestEditsPartiallyApplied(t *testing.T) {
+	// 写入过程中崩溃时，记录中只有部分文件已经替换
+	dir := t.TempDir()
+	edits := journalFiles(t, dir, "一", "二", "三")
+	if _, err := ApplyEdits(edits[:1], false); err != nil {
+		t.Fatal(err)
+	}
+
+	// 已经替换的文件视为写入成功，不会被当作外部修改
+	applied, err := ApplyEdits(edits, false)
+	if err != nil || len(applied) != 3 {
+		t.Errorf("再次替换写入 %d 个文件，出错：%v，应为 3 个", len(applied), err)
+	}
+
+	// 恢复只替换了一部分的记录时，尚未替换的文件同样视为已恢复
+	if _, err := RevertEdits(edits[:1], false); err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := RevertEdits(edits, false)
+	if err != nil || len(reverted) != 3 {
+		t.Errorf("恢复 %d 个文件，出错：%v，应为 3 个", len(reverted), err)
+	}
+	for _, edit := range edits {
+		if got := readFile(t, edit.Path); got != edit.Before {
+			t.Errorf("%s 的内容为 %q，应为 %q", filepath.Base(edit.Path), got, edit.Before)
+		}
+	}
+}
+
+func TestEditsMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	edits := journalFiles(t, dir, "一", "二")
+	if err := os.Remove(edits[0].Path); err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := ApplyEdits(edits, false)
+	if err == nil || len(applied) != 1 {
+		t.Errorf("写入 %d 个文件，出错：%v，应写入 1 个并报告错误", len(applied), err)
+	}
+	if _, statErr := os.Stat(edits[0].Path); statErr == nil {
+		t.Error("被删除的文件不应重新创建")
+	}
+}
+
+func TestJournalStore(t *testing.T) {
+	store, err := NewJournalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest, err := store.Latest(); latest != nil || err != nil {
+		t.Fatalf("没有记录时 Latest() = %v, %v，应为 nil", latest, err)
+	}
+
+	now := time.Now()
+	older := &ReplaceJournal{ID: "older", Description: "a → b", CreatedAt: now.Add(-time.Hour), Files: []FileEdit{{Path: "/x.md"}}}
+	newer := &ReplaceJournal{ID: "newer", Description: "c → d", CreatedAt: now, Files: []FileEdit{{Path: "/y.md"}, {Path: "/z.md"}}}
+	for _, journal := range []*ReplaceJournal{older, newer} {
+		if err := store.Save(journal); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 无法解析和ID与文件名不一致的文件被忽略
+	writeFile(t, filepath.Join(store.dir, "broken.json"), "{", 0600)
+	writeFile(t, filepath.Join(store.dir, "other.json"), `{"id":"../outside"}`, 0600)
+
+	journals, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 2 || journals[0].ID != "newer" || journals[1].ID != "older" {
+		t.Fatalf("List() 返回 %d 条记录，应按时间从新到旧返回 newer 和 older", len(journals))
+	}
+	if latest, _ := store.Latest(); latest == nil || latest.ID != "newer" {
+		t.Errorf("Latest() = %+v，应为 newer", latest)
+	}
+
+	// 恢复部分文件后记录中只剩其余文件
+	if err := store.RemoveFiles(journals[0], []FileEdit{{Path: "/y.md"}}); err != nil {
+		t.Fatal(err)
+	}
+	journals, _ = store.List()
+	if len(journals) != 2 || len(journals[0].Files) != 1 || journals[0].Files[0].Path != "/z.md" {
+		t.Errorf("恢复一个文件后记录为 %+v，应只剩 /z.md", journals[0])
+	}
+
+	// 所有文件都恢复后删除记录
+	if err := store.RemoveFiles(journals[0], []FileEdit{{Path: "/z.md"}}); err != nil {
+		t.Fatal(err)
+	}
+	journals, _ = store.List()
+	if len(journals) != 1 || journals[0].ID != "older" {
+		t.Errorf("全部恢复后还有 %d 条记录，应只剩 older", len(journals))
+	}
+
+	if err := store.Remove("missing"); err != nil {
+		t.Errorf("删除不存在的记录出错：%v", err)
+	}
+}
+
+func TestJournalStorePrune(t *testing.T) {
+	store, err := NewJournalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for i := 0; i < maxJournals+5; i++ {
+		journal := &ReplaceJournal{ID: fmt.Sprintf("j%02d", i), CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		if err := store.Save(journal); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	journals, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != maxJournals {
+		t.Fatalf("保存后有 %d 条记录，应最多保留 %d 条", len(journals), maxJournals)
+	}
+	// 删除的是最早的记录
+	if newest, oldest := journals[0].ID, journals[len(journals)-1].ID; newest != fmt.Sprintf("j%02d", maxJournals+4) || oldest != "j05" {
+		t.Errorf("保留的记录为 %s 到 %s，应为 j%02d 到 j05", oldest, newest, maxJournals+4)
+	}
+}
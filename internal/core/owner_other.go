@@ -0,0 +1,8 @@
+//go:build !unix
+
+package core
+
+import "io/fs"
+
+// preserveOwner 非 Unix 平台的文件没有 uid/gid，无需处理
+func preserveOwner(path string, original fs.FileInfo) {}
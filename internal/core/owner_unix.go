@@ -0,0 +1,16 @@
+//go:build unix
+
+package core
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// preserveOwner 将文件的所有者设置为与原文件一致
+func preserveOwner(path string, original fs.FileInfo) {
+	if stat, ok := original.Sys().(*syscall.Stat_t); ok {
+		os.Lchown(path, int(stat.Uid), int(stat.Gid))
+	}
+}
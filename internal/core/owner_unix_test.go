@@ -0,0 +1,36 @@
+//go:build unix
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestWriteFileAtomicOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("修改文件所有者需要 root 权限")
+	}
+
+	path := filepath.Join(t.TempDir(), "a.md")
+	writeFile(t, path, "旧内容", 0644)
+	const uid, gid = 12345, 23456
+	if err := os.Chown(path, uid, gid); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("新内容"), false); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat := info.Sys().(*syscall.Stat_t)
+	if stat.Uid != uid || stat.Gid != gid {
+		t.Errorf("文件所有者为 %d:%d，应为 %d:%d", stat.Uid, stat.Gid, uid, gid)
+	}
+}
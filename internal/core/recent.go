@@ -0,0 +1,204 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RecentFileName 最近打开列表的文件名
+const RecentFileName = "recent.json"
+
+// MaxRecentEntries 最近打开列表中保留的未固定条目数量
+const MaxRecentEntries = 10
+
+// RecentEntry 最近打开的文件或文件夹
+type RecentEntry struct {
+	Path     string    `json:"path"`             // 绝对路径
+	IsDir    bool      `json:"is_dir,omitempty"` // 是否为文件夹（工作区）
+	Pinned   bool      `json:"pinned,omitempty"` // 是否固定，固定的条目不会被挤出列表或清除
+	OpenedAt time.Time `json:"opened_at"`        // 最近一次打开的时间
+}
+
+// RecentList 最近打开的文件和文件夹列表，每次修改后立即保存
+type RecentList struct {
+	mutex   sync.Mutex
+	path    string         // 保存列表的文件
+	entries []*RecentEntry // 固定的在前，其余按打开时间从新到旧
+}
+
+// LoadRecentList 加载最近打开列表，path 为空时使用用户配置目录下的 recent.json
+// 文件不存在或无法解析时返回空列表
+func LoadRecentList(path string) (*RecentList, error) {
+	if path == "" {
+		dir, err := ConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, RecentFileName)
+	}
+
+	list := &RecentList{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return list, nil
+	}
+	if err != nil {
+		return list, err
+	}
+
+	var entries []*RecentEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return list, err
+	}
+	for _, entry := range entries {
+		if entry != nil && entry.Path != "" {
+			list.entries = append(list.entries, entry)
+		}
+	}
+	list.normalize()
+	return list, nil
+}
+
+// Entries 获取列表条目的副本，固定的在前，其余按打开时间从新到旧
+func (l *RecentList) Entries() []RecentEntry {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entries := make([]RecentEntry, len(l.entries))
+	for i, entry := range l.entries {
+		entries[i] = *entry
+	}
+	return entries
+}
+
+// Add 记录打开了指定文件或文件夹，已在列表中时更新打开时间
+func (l *RecentList) Add(path string, isDir bool) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if entry := l.find(path); entry != nil {
+		entry.IsDir = isDir
+		entry.OpenedAt = time.Now()
+	} else {
+		l.entries = append(l.entries, &RecentEntry{Path: path, IsDir: isDir, OpenedAt: time.Now()})
+	}
+	l.normalize()
+	return l.save()
+}
+
+// Remove 从列表中移除指定路径
+func (l *RecentList) Remove(path string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i, entry := range l.entries {
+		if entry.Path == path {
+			l.entries = append(l.entries[:i], l.entries[i+1:]...)
+			return l.save()
+		}
+	}
+	return nil
+}
+
+// SetPinned 固定或取消固定指定路径
+func (l *RecentList) SetPinned(path string, pinned bool) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry := l.find(path)
+	if entry == nil || entry.Pinned == pinned {
+		return nil
+	}
+	entry.Pinned = pinned
+	l.normalize()
+	return l.save()
+}
+
+// Clear 清除所有未固定的条目
+func (l *RecentList) Clear() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var pinned []*RecentEntry
+	for _, entry := range l.entries {
+		if entry.Pinned {
+			pinned = append(pinned, entry)
+		}
+	}
+	l.entries = pinned
+	return l.save()
+}
+
+// Prune 移除已不存在的文件和文件夹，返回移除的条目数量
+func (l *RecentList) Prune() (int, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var kept []*RecentEntry
+	for _, entry := range l.entries {
+		info, err := os.Stat(entry.Path)
+		if err != nil || info.IsDir() != entry.IsDir {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+
+	removed := len(l.entries) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	l.entries = kept
+	return removed, l.save()
+}
+
+// find 查找指定路径的条目，调用方需持有锁
+func (l *RecentList) find(path string) *RecentEntry {
+	for _, entry := range l.entries {
+		if entry.Path == path {
+			return entry
+		}
+	}
+	return nil
+}
+
+// normalize 排序并去掉超出数量的未固定条目，调用方需持有锁
+func (l *RecentList) normalize() {
+	sort.SliceStable(l.entries, func(i, j int) bool {
+		if l.entries[i].Pinned != l.entries[j].Pinned {
+			return l.entries[i].Pinned
+		}
+		return l.entries[i].OpenedAt.After(l.entries[j].OpenedAt)
+	})
+
+	unpinned := 0
+	kept := l.entries[:0]
+	for _, entry := range l.entries {
+		if !entry.Pinned {
+			unpinned++
+			if unpinned > MaxRecentEntries {
+				continue
+			}
+		}
+		kept = append(kept, entry)
+	}
+	l.entries = kept
+}
+
+// save 保存列表（原子写入），调用方需持有锁
+func (l *RecentList) save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(l.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(l.path, data, false)
+}
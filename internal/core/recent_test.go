@@ -0,0 +1,211 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// recentPaths 获取列表中的路径，固定的条目后加 *
+func recentPaths(list *RecentList) []string {
+	var paths []string
+	for _, entry := range list.Entries() {
+		path := entry.Path
+		if entry.Pinned {
+			path += "*"
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// newRecentList 创建最近打开列表，entries 按给定的顺序从新到旧设置打开时间
+func newRecentList(t *testing.T, entries ...*RecentEntry) *RecentList {
+	t.Helper()
+	list, err := LoadRecentList(filepath.Join(t.TempDir(), RecentFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i, entry := range entries {
+		entry.OpenedAt = now.Add(-time.Duration(i+1) * time.Minute)
+		list.entries = append(list.entries, entry)
+	}
+	list.normalize()
+	return list
+}
+
+func TestRecentListOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(list *RecentList) error
+		want   []string
+	}{
+		{"按打开时间从新到旧", nil, []string{"a", "b", "c"}},
+		{"再次打开移到最前", func(l *RecentList) error { return l.Add("c", false) }, []string{"c", "a", "b"}},
+		{"新打开的在最前", func(l *RecentList) error { return l.Add("d", true) }, []string{"d", "a", "b", "c"}},
+		{"固定的在最前", func(l *RecentList) error { return l.SetPinned("c", true) }, []string{"c*", "a", "b"}},
+		{
+			"固定的条目之间按打开时间排序",
+			func(l *RecentList) error {
+				if err := l.SetPinned("c", true); err != nil {
+					return err
+				}
+				return l.SetPinned("b", true)
+			},
+			[]string{"b*", "c*", "a"},
+		},
+		{
+			"新打开的排在固定的之后",
+			func(l *RecentList) error {
+				if err := l.SetPinned("c", true); err != nil {
+					return err
+				}
+				return l.Add("d", false)
+			},
+			[]string{"c*", "d", "a", "b"},
+		},
+		{
+			"取消固定后按打开时间排序",
+			func(l *RecentList) error {
+				if err := l.SetPinned("c", true); err != nil {
+					return err
+				}
+				return l.SetPinned("c", false)
+			},
+			[]string{"a", "b", "c"},
+		},
+		{"固定不存在的路径", func(l *RecentList) error { return l.SetPinned("x", true) }, []string{"a", "b", "c"}},
+		{"移除", func(l *RecentList) error { return l.Remove("b") }, []string{"a", "c"}},
+		{
+			"清除保留固定的条目",
+			func(l *RecentList) error {
+				if err := l.SetPinned("b", true); err != nil {
+					return err
+				}
+				return l.Clear()
+			},
+			[]string{"b*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := newRecentList(t, &RecentEntry{Path: "a"}, &RecentEntry{Path: "b"}, &RecentEntry{Path: "c"})
+			if tt.update != nil {
+				if err := tt.update(list); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := recentPaths(list); !equalStrings(got, tt.want) {
+				t.Errorf("列表为 %q，应为 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecentListLimit(t *testing.T) {
+	var entries []*RecentEntry
+	for i := 0; i < MaxRecentEntries; i++ {
+		entries = append(entries, &RecentEntry{Path: fmt.Sprint(i)})
+	}
+	entries[MaxRecentEntries-1].Pinned = true
+	list := newRecentList(t, entries...)
+
+	// 超出数量时挤掉最早打开的未固定条目，固定的条目不计入数量
+	if err := list.Add("new", false); err != nil {
+		t.Fatal(err)
+	}
+	got := recentPaths(list)
+	if len(got) != MaxRecentEntries+1 {
+		t.Fatalf("列表中有 %d 个条目，应为 %d", len(got), MaxRecentEntries+1)
+	}
+	if err := list.Add("newer", false); err != nil {
+		t.Fatal(err)
+	}
+	got = recentPaths(list)
+	want := []string{fmt.Sprintf("%d*", MaxRecentEntries-1), "newer", "new"}
+	for i := 0; i < MaxRecentEntries-2; i++ {
+		want = append(want, fmt.Sprint(i))
+	}
+	if !equalStrings(got, want) {
+		t.Errorf("列表为 %q，应为 %q", got, want)
+	}
+}
+
+func TestRecentListPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", RecentFileName)
+
+	// 文件不存在时返回空列表
+	list, err := LoadRecentList(path)
+	if err != nil || len(list.Entries()) != 0 {
+		t.Fatalf("LoadRecentList() 返回 %d 个条目，出错：%v，应为空列表", len(list.Entries()), err)
+	}
+
+	if err := list.Add("/docs/a.md", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Add("/docs", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.SetPinned("/docs/a.md", true); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadRecentList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := recentPaths(loaded), []string{"/docs/a.md*", "/docs"}; !equalStrings(got, want) {
+		t.Errorf("重新加载后列表为 %q，应为 %q", got, want)
+	}
+	if entries := loaded.Entries(); entries[0].IsDir || !entries[1].IsDir {
+		t.Errorf("重新加载后的条目为 %+v，文件夹标记不正确", entries)
+	}
+
+	// 无法解析的文件返回空列表和错误，空路径和 null 条目被忽略
+	writeFile(t, path, "{", 0600)
+	if list, err := LoadRecentList(path); err == nil || len(list.Entries()) != 0 {
+		t.Errorf("文件无法解析时返回 %d 个条目，错误为 %v", len(list.Entries()), err)
+	}
+	writeFile(t, path, `[null, {"path": ""}, {"path": "/docs/b.md"}]`, 0600)
+	if list, err := LoadRecentList(path); err != nil || !equalStrings(recentPaths(list), []string{"/docs/b.md"}) {
+		t.Errorf("LoadRecentList() = %q，出错：%v，应只包含 /docs/b.md", recentPaths(list), err)
+	}
+}
+
+func TestRecentListPrune(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.md")
+	writeFile(t, file, "# 标题", 0644)
+	folder := filepath.Join(dir, "docs")
+	if err := os.Mkdir(folder, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	list := newRecentList(t,
+		&RecentEntry{Path: file},
+		&RecentEntry{Path: folder, IsDir: true},
+		&RecentEntry{Path: filepath.Join(dir, "missing.md")},
+		&RecentEntry{Path: filepath.Join(dir, "missing"), IsDir: true, Pinned: true},
+		&RecentEntry{Path: folder}, // 类型不一致
+	)
+
+	removed, err := list.Prune()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 {
+		t.Errorf("Prune() 移除了 %d 个条目，应为 3", removed)
+	}
+	if got, want := recentPaths(list), []string{file, folder}; !equalStrings(got, want) {
+		t.Errorf("Prune() 后列表为 %q，应为 %q", got, want)
+	}
+
+	// 没有需要移除的条目
+	if removed, err := list.Prune(); err != nil || removed != 0 {
+		t.Errorf("再次 Prune() 移除了 %d 个条目，出错：%v，应为 0", removed, err)
+	}
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SessionFileName 会话文件名
+const SessionFileName = "session.json"
+
+// SessionDocument 会话中打开的一个文档
+type SessionDocument struct {
+	Path         string  `json:"path"`          // 文件路径
+	CursorRow    int     `json:"cursor_row"`    // 光标所在行（从0开始）
+	CursorColumn int     `json:"cursor_column"` // 光标所在列（从0开始）
+	ScrollX      float32 `json:"scroll_x"`      // 编辑器水平滚动位置
+	ScrollY      float32 `json:"scroll_y"`      // 编辑器垂直滚动位置
+}
+
+// Session 退出时的界面状态，保存在用户配置目录下的 session.json 中，下次启动时恢复
+type Session struct {
+	Workspace    string             `json:"workspace,omitempty"`   // 打开的工作区目录
+	Documents    []SessionDocument  `json:"documents,omitempty"`   // 打开的文档，顺序与标签页一致
+	ActiveFile   string             `json:"active_file,omitempty"` // 当前标签页的文件
+	WindowWidth  float32            `json:"window_width,omitempty"`
+	WindowHeight float32            `json:"window_height,omitempty"`
+	Splits       map[string]float64 `json:"splits,omitempty"`    // 各分屏的比例，键为分屏名称
+	ViewMode     string             `json:"view_mode,omitempty"` // 编辑区显示模式
+	Theme        string             `json:"theme,omitempty"`     // 配色：light 或 dark
+	Zoom         float32            `json:"zoom,omitempty"`      // 文字缩放比例
+}
+
+// sessionPath 获取会话文件路径
+func sessionPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, SessionFileName), nil
+}
+
+// LoadSession 加载上次保存的会话，会话文件不存在时返回空会话
+func LoadSession() (*Session, error) {
+	path, err := sessionPath()
+	if err != nil {
+		return &Session{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Session{}, nil
+	}
+	if err != nil {
+		return &Session{}, err
+	}
+
+	session := &Session{}
+	if err := json.Unmarshal(data, session); err != nil {
+		return &Session{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return session, nil
+}
+
+// SaveSession 保存会话（原子写入）
+func SaveSession(session *Session) error {
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, data, false)
+}
+
+// SplitOffset 获取指定分屏保存的比例，没有保存或比例无效时返回 fallback
+func (s *Session) SplitOffset(name string, fallback float64) float64 {
+	offset, ok := s.Splits[name]
+	if !ok || offset <= 0 || offset >= 1 {
+		return fallback
+	}
+	return offset
+}
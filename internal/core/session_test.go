@@ -0,0 +1,119 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// useTempConfigDir 把用户配置目录指向临时目录，返回会话文件路径
+func useTempConfigDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("AppData", dir)
+
+	path, err := sessionPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	path := useTempConfigDir(t)
+
+	// 会话文件不存在时返回空会话
+	session, err := LoadSession()
+	if err != nil || !reflect.DeepEqual(session, &Session{}) {
+		t.Fatalf("LoadSession() = %+v，出错：%v，应为空会话", session, err)
+	}
+
+	want := &Session{
+		Workspace: "/docs",
+		Documents: []SessionDocument{
+			{Path: "/docs/a.md", CursorRow: 3, CursorColumn: 5, ScrollY: 120.5},
+			{Path: "/docs/中文.md", ScrollX: 10},
+		},
+		ActiveFile:   "/docs/中文.md",
+		WindowWidth:  1200,
+		WindowHeight: 800,
+		Splits:       map[string]float64{"sidebar": 0.2, "editor": 0.55},
+		ViewMode:     "split",
+		Theme:        "dark",
+		Zoom:         1.25,
+	}
+	if err := SaveSession(want); err != nil {
+		t.Fatalf("SaveSession() 出错：%v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("会话文件没有写入 %s：%v", path, err)
+	}
+
+	got, err := LoadSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadSession() =\n%+v\n应为\n%+v", got, want)
+	}
+
+	// 空字段不写入文件
+	if err := SaveSession(&Session{}); err != nil {
+		t.Fatal(err)
+	}
+	if data := readFile(t, path); data != "{}" {
+		t.Errorf("空会话保存为 %q，应为 {}", data)
+	}
+}
+
+func TestLoadSessionInvalid(t *testing.T) {
+	path := useTempConfigDir(t)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "{", 0600)
+
+	session, err := LoadSession()
+	if err == nil {
+		t.Error("会话文件无法解析时应出错")
+	}
+	if !reflect.DeepEqual(session, &Session{}) {
+		t.Errorf("会话文件无法解析时返回 %+v，应为空会话", session)
+	}
+}
+
+func TestSessionSplitOffset(t *testing.T) {
+	session := &Session{Splits: map[string]float64{
+		"sidebar": 0.25,
+		"zero":    0,
+		"one":     1,
+		"over":    1.5,
+		"under":   -0.1,
+	}}
+
+	tests := []struct {
+		name string
+		want float64
+	}{
+		{"sidebar", 0.25},
+		{"missing", 0.5},
+		{"zero", 0.5},
+		{"one", 0.5},
+		{"over", 0.5},
+		{"under", 0.5},
+	}
+
+	for _, tt := range tests {
+		if got := session.SplitOffset(tt.name, 0.5); got != tt.want {
+			t.Errorf("SplitOffset(%q) = %v，应为 %v", tt.name, got, tt.want)
+		}
+	}
+
+	// 没有保存任何分屏
+	if got := (&Session{}).SplitOffset("sidebar", 0.3); got != 0.3 {
+		t.Errorf("没有保存分屏时 SplitOffset() = %v，应为 0.3", got)
+	}
+}
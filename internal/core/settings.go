@@ -0,0 +1,67 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// SettingsFileName 用户设置文件名
+const SettingsFileName = "settings.json"
+
+// Settings 用户设置，保存在用户配置目录下的 settings.json 中
+type Settings struct {
+	// AutosaveInterval 自动保存草稿的间隔（秒），0 表示关闭自动保存
+	AutosaveInterval int `json:"autosave_interval"`
+	// KeepBackup 保存时是否把上一版本保留为同名的 .bak 文件
+	KeepBackup bool `json:"keep_backup"`
+	// Keybindings 覆盖默认快捷键，键为命令ID，值为快捷键（如 "Mod+Shift+S"），空字符串表示取消绑定
+	Keybindings map[string]string `json:"keybindings,omitempty"`
+}
+
+// DefaultSettings 返回默认设置
+func DefaultSettings() *Settings {
+	return &Settings{
+		AutosaveInterval: 30,
+	}
+}
+
+// ConfigDir 获取应用的用户配置目录
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "markup"), nil
+}
+
+// LoadSettings 加载用户设置，设置文件不存在时返回默认设置
+// 文件中未出现的字段保持默认值
+func LoadSettings() (*Settings, error) {
+	settings := DefaultSettings()
+
+	dir, err := ConfigDir()
+	if err != nil {
+		return settings, err
+	}
+
+	path := filepath.Join(dir, SettingsFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+
+	if err := json.Unmarshal(data, settings); err != nil {
+		return DefaultSettings(), fmt.Errorf("%s: %w", path, err)
+	}
+	if settings.AutosaveInterval < 0 {
+		settings.AutosaveInterval = 0
+	}
+	return settings, nil
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// FileSnapshot 文件在磁盘上的状态，用于检测外部修改
+type FileSnapshot struct {
+	Exists  bool              // 文件是否存在
+	ModTime time.Time         // 修改时间
+	Size    int64             // 文件大小
+	Hash    [sha256.Size]byte // 内容哈希
+}
+
+// TakeSnapshot 读取文件在磁盘上的状态，文件不存在时返回 Exists 为 false 的快照
+func TakeSnapshot(path string) (FileSnapshot, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return FileSnapshot{}, nil
+	}
+	if err != nil {
+		return FileSnapshot{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FileSnapshot{}, err
+	}
+
+	return FileSnapshot{
+		Exists:  true,
+		ModTime: info.ModTime(),
+		Size:    info.Size(),
+		Hash:    sha256.Sum256(data),
+	}, nil
+}
+
+// statChanged 快速判断文件的修改时间或大小是否与快照不同
+func (snap FileSnapshot) statChanged(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return snap.Exists, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !snap.Exists || !info.ModTime().Equal(snap.ModTime) || info.Size() != snap.Size, nil
+}
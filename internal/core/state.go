@@ -7,9 +7,10 @@ import (
 
 // OutlineEntry 代表大纲中的一个条目
 type OutlineEntry struct {
-	Title string // 标题文本
+	Title string // 标题文本（已去除行内标记）
 	Level int    // 标题级别 (1-6)
 	Line  int    // 行号
+	ID    string // 标题锚点ID（与渲染的HTML一致）
 }
 
 // AppState 应用状态管理
@@ -19,6 +20,8 @@ type AppState struct {
 	currentContent  string         // 当前文件内容
 	originalContent string         // 原始文件内容（用于检测变更）
 	outline         []OutlineEntry // 文档大纲
+	keepBackup      bool           // 保存时是否保留上一版本的 .bak 文件
+	diskSnapshot    FileSnapshot   // 最近一次加载或保存时文件在磁盘上的状态
 }
 
 // NewAppState 创建新的应用状态实例
@@ -103,9 +106,74 @@ func (s *AppState) LoadFile(filePath string) (string, error) {
 	return string(content), nil
 }
 
-// SaveFile 保存文件内容
+// SetKeepBackup 设置保存时是否保留上一版本的 .bak 文件
+func (s *AppState) SetKeepBackup(keep bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.keepBackup = keep
+}
+
+// SaveFile 保存文件内容（原子写入，保留原文件的权限和所有者）
 func (s *AppState) SaveFile(filePath, content string) error {
-	return os.WriteFile(filePath, []byte(content), 0644)
+	s.mutex.RLock()
+	keepBackup := s.keepBackup
+	s.mutex.RUnlock()
+
+	return WriteFileAtomic(filePath, []byte(content), keepBackup)
+}
+
+// UpdateDiskSnapshot 记录当前文件在磁盘上的状态，在加载或保存当前文件后调用
+func (s *AppState) UpdateDiskSnapshot() error {
+	filePath := s.GetCurrentFile()
+
+	var snapshot FileSnapshot
+	if filePath != "" {
+		var err error
+		if snapshot, err = TakeSnapshot(filePath); err != nil {
+			return err
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.diskSnapshot = snapshot
+	return nil
+}
+
+// SetDiskSnapshot 设置已知的磁盘状态（例如用户确认保留自己的版本后接受新的磁盘状态）
+func (s *AppState) SetDiskSnapshot(snapshot FileSnapshot) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.diskSnapshot = snapshot
+}
+
+// CheckDiskChange 检查当前文件是否被外部修改，修改时返回新的磁盘状态
+// 先比较修改时间和大小，变化时再比较内容哈希，避免仅修改时间变化造成误报
+func (s *AppState) CheckDiskChange() (bool, FileSnapshot, error) {
+	filePath := s.GetCurrentFile()
+	s.mutex.RLock()
+	snapshot := s.diskSnapshot
+	s.mutex.RUnlock()
+
+	if filePath == "" {
+		return false, snapshot, nil
+	}
+
+	changed, err := snapshot.statChanged(filePath)
+	if err != nil || !changed {
+		return false, snapshot, err
+	}
+
+	current, err := TakeSnapshot(filePath)
+	if err != nil {
+		return false, snapshot, err
+	}
+	if current.Exists == snapshot.Exists && current.Hash == snapshot.Hash {
+		// 内容没有变化，只更新记录的修改时间
+		s.SetDiskSnapshot(current)
+		return false, current, nil
+	}
+	return true, current, nil
 }
 
 // Reset 重置状态
@@ -116,4 +184,5 @@ func (s *AppState) Reset() {
 	s.currentContent = ""
 	s.originalContent = ""
 	s.outline = make([]OutlineEntry, 0)
+	s.diskSnapshot = FileSnapshot{}
 }
@@ -0,0 +1,107 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigFileName 检查配置文件名
+const ConfigFileName = ".markuplint.json"
+
+// Config 检查配置
+//
+// 配置文件示例：
+//
+//	{
+//	  "rules": {
+//	    "trailing-whitespace": "off",
+//	    "heading-increment": "error"
+//	  }
+//	}
+//
+// 每条规则的值可以是 "off"（关闭）或严重级别 "error"、"warning"、"info"，
+// 未列出的规则使用默认级别。
+type Config struct {
+	Rules map[string]string `json:"rules"` // 规则ID -> 级别或 "off"
+}
+
+// DefaultConfig 返回默认配置（启用所有规则并使用默认级别）
+func DefaultConfig() *Config {
+	return &Config{Rules: make(map[string]string)}
+}
+
+// LoadConfig 从文件加载配置，并校验规则ID和级别
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// FindConfig 从 dir 开始逐级向上查找配置文件，找不到时返回空字符串
+func FindConfig(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ConfigFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// validate 校验配置中的规则ID和级别
+func (c *Config) validate() error {
+	known := make(map[string]bool)
+	for _, rule := range Rules() {
+		known[rule.ID] = true
+	}
+
+	for id, value := range c.Rules {
+		if !known[id] {
+			return fmt.Errorf("未知的规则 %q", id)
+		}
+		if strings.EqualFold(value, "off") {
+			continue
+		}
+		if _, err := ParseSeverity(value); err != nil {
+			return fmt.Errorf("规则 %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// severityFor 获取规则在该配置下的级别，以及规则是否启用
+func (c *Config) severityFor(rule *Rule) (Severity, bool) {
+	value, ok := c.Rules[rule.ID]
+	if !ok {
+		return rule.DefaultSeverity, true
+	}
+	if strings.EqualFold(value, "off") {
+		return rule.DefaultSeverity, false
+	}
+	severity, err := ParseSeverity(value)
+	if err != nil {
+		return rule.DefaultSeverity, true
+	}
+	return severity, true
+}
@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string // 错误信息中应包含的内容，为空表示应加载成功
+	}{
+		{"空配置", `{}`, ""},
+		{"关闭规则和修改级别", `{"rules": {"trailing-whitespace": "off", "heading-increment": "error"}}`, ""},
+		{"级别不区分大小写", `{"rules": {"heading-space": "Warning", "empty-link": "OFF"}}`, ""},
+		{"未知的规则", `{"rules": {"no-such-rule": "error"}}`, "未知的规则"},
+		{"未知的级别", `{"rules": {"heading-space": "fatal"}}`, "未知的严重级别"},
+		{"规则的值不是字符串", `{"rules": {"heading-space": 1}}`, "cannot unmarshal"},
+		{"不是 JSON", `rules: off`, "invalid character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ConfigFileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := LoadConfig(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadConfig() 出错：%v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("LoadConfig() = %v，应出错", config)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), path) {
+				t.Errorf("LoadConfig() 的错误为 %q，应包含 %q 和文件路径", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), ConfigFileName)); !os.IsNotExist(err) {
+		t.Errorf("LoadConfig() 的错误为 %v，应为文件不存在", err)
+	}
+}
+
+func TestConfigRules(t *testing.T) {
+	content := "#标题\n# 一\n### 三\n正文 "
+
+	tests := []struct {
+		name  string
+		rules map[string]string
+		want  []string // "规则 级别"
+	}{
+		{"默认配置", nil, []string{"heading-space error", "heading-increment warning", "trailing-whitespace info"}},
+		{"关闭规则", map[string]string{"trailing-whitespace": "off", "heading-space": "OFF"}, []string{"heading-increment warning"}},
+		{"修改级别", map[string]string{"heading-increment": "error", "heading-space": "info"}, []string{"heading-space info", "heading-increment error", "trailing-whitespace info"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			for id, value := range tt.rules {
+				config.Rules[id] = value
+			}
+
+			var got []string
+			for _, d := range NewLinter(config).Lint(content) {
+				got = append(got, d.RuleID+" "+d.Severity.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v，应为 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "docs", "guide")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// 上级目录都没有配置文件时找不到（临时目录之外可能有，只检查不会返回临时目录中的路径）
+	if path := FindConfig(nested); strings.HasPrefix(path, root) {
+		t.Errorf("FindConfig() = %q，不应找到配置文件", path)
+	}
+
+	rootConfig := filepath.Join(root, ConfigFileName)
+	if err := os.WriteFile(rootConfig, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if path := FindConfig(nested); path != rootConfig {
+		t.Errorf("FindConfig() = %q，应找到上级目录中的 %q", path, rootConfig)
+	}
+
+	// 较近的配置文件优先
+	docsConfig := filepath.Join(root, "docs", ConfigFileName)
+	if err := os.WriteFile(docsConfig, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if path := FindConfig(nested); path != docsConfig {
+		t.Errorf("FindConfig() = %q，应为 %q", path, docsConfig)
+	}
+
+	// 与配置文件同名的目录不是配置文件
+	if err := os.Mkdir(filepath.Join(nested, ConfigFileName), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if path := FindConfig(nested); path != docsConfig {
+		t.Errorf("FindConfig() = %q，应跳过同名目录找到 %q", path, docsConfig)
+	}
+}
@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity 诊断的严重级别
+type Severity int
+
+const (
+	SeverityInfo    Severity = iota // 提示
+	SeverityWarning                 // 警告
+	SeverityError                   // 错误
+)
+
+// severityNames 严重级别名称，顺序与 Severity 常量一致
+var severityNames = []string{"info", "warning", "error"}
+
+// String 返回严重级别名称
+func (s Severity) String() string {
+	if int(s) < 0 || int(s) >= len(severityNames) {
+		return "unknown"
+	}
+	return severityNames[s]
+}
+
+// MarshalText 以名称形式序列化严重级别
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText 从名称解析严重级别
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// ParseSeverity 从名称解析严重级别
+func ParseSeverity(name string) (Severity, error) {
+	for i, severityName := range severityNames {
+		if strings.EqualFold(name, severityName) {
+			return Severity(i), nil
+		}
+	}
+	return SeverityInfo, fmt.Errorf("未知的严重级别 %q", name)
+}
+
+// Diagnostic 一条检查结果
+type Diagnostic struct {
+	RuleID   string   `json:"rule"`     // 规则ID
+	Severity Severity `json:"severity"` // 严重级别
+	Line     int      `json:"line"`     // 行号（从1开始）
+	Column   int      `json:"column"`   // 列号（从1开始，按字符计算）
+	Message  string   `json:"message"`  // 说明
+}
+
+// String 返回 "行:列: 级别 [规则] 说明" 格式的文本
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s [%s] %s", d.Line, d.Column, d.Severity, d.RuleID, d.Message)
+}
+
+// Linter Markdown检查器
+type Linter struct {
+	rules []*Rule // 启用的规则
+	// severities 各规则实际使用的严重级别（配置可覆盖默认级别）
+	severities map[string]Severity
+}
+
+// NewLinter 按配置创建检查器，config 为 nil 时使用默认配置
+func NewLinter(config *Config) *Linter {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	l := &Linter{severities: make(map[string]Severity)}
+	for _, rule := range Rules() {
+		severity, enabled := config.severityFor(rule)
+		if !enabled {
+			continue
+		}
+		l.rules = append(l.rules, rule)
+		l.severities[rule.ID] = severity
+	}
+	return l
+}
+
+// Lint 检查Markdown内容，结果按位置排序
+func (l *Linter) Lint(content string) []Diagnostic {
+	doc := newDocument(content)
+
+	var diagnostics []Diagnostic
+	for _, rule := range l.rules {
+		for _, d := range rule.check(doc) {
+			d.RuleID = rule.ID
+			d.Severity = l.severities[rule.ID]
+			diagnostics = append(diagnostics, d)
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Line != diagnostics[j].Line {
+			return diagnostics[i].Line < diagnostics[j].Line
+		}
+		return diagnostics[i].Column < diagnostics[j].Column
+	})
+	return diagnostics
+}
+
+// HasErrors 判断结果中是否包含错误级别的诊断
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package lint
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// summarize 把诊断转换为 "行:列 规则" 格式，便于比较
+func summarize(diagnostics []Diagnostic) []string {
+	var result []string
+	for _, d := range diagnostics {
+		result = append(result, fmt.Sprintf("%d:%d %s", d.Line, d.Column, d.RuleID))
+	}
+	return result
+}
+
+func TestLintRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"没有问题", "# 标题\n\n正文 [链接](https://example.com)\n", nil},
+
+		// heading-space
+		{"标题缺少空格", "#标题", []string{"1:2 heading-space"}},
+		{"多级标题缺少空格", "###标题", []string{"1:4 heading-space"}},
+		{"缩进的标题缺少空格", "   ##标题", []string{"1:6 heading-space"}},
+		{"只有 # 的空标题", "#", nil},
+		{"超过六个 # 不是标题", "#######标题", nil},
+
+		// heading-increment
+		{"标题跳级", "# 一\n### 三", []string{"2:1 heading-increment"}},
+		{"标题逐级递增", "# 一\n## 二\n### 三\n# 一", nil},
+		{"第一个标题不检查级别", "### 三\n#### 四", nil},
+
+		// link-syntax
+		{"链接缺少右括号", "见 [文本](地址", []string{"1:6 link-syntax"}},
+		{"链接缺少文本部分", "见 ](地址)", []string{"1:3 link-syntax"}},
+
+		// empty-link
+		{"空链接", "[文本]()", []string{"1:1 empty-link"}},
+		{"只有空白的链接地址", "前 [文本]( ) 后 [b]()", []string{"1:3 empty-link", "1:13 empty-link"}},
+
+		// unclosed-code-fence
+		{"未闭合的代码块", "正文\n```go\ncode", []string{"2:1 unclosed-code-fence"}},
+		{"闭合的代码块", "```\ncode\n```", nil},
+		{"较短的围栏不能闭合代码块", "````\ncode\n```", []string{"1:1 unclosed-code-fence"}},
+		{"不同字符的围栏不能闭合代码块", "~~~\ncode\n```", []string{"1:1 unclosed-code-fence"}},
+		{"带信息的围栏不能闭合代码块", "```\ncode\n``` go", []string{"1:1 unclosed-code-fence"}},
+
+		// trailing-whitespace
+		{"行尾空白", "正文 ", []string{"1:3 trailing-whitespace"}},
+		{"行尾制表符", "正文\t", []string{"1:3 trailing-whitespace"}},
+		{"两个空格的硬换行", "正文  \n下一行", nil},
+		{"三个空格", "正文   ", []string{"1:3 trailing-whitespace"}},
+		{"只有空白的行", "   ", nil},
+
+		// 代码块内不检查 Markdown 语法
+		{"代码块内的标题和链接", "```\n#标题\n# 一\n### 三\n[文本]()\n[文本](地址\n```", nil},
+		{"代码块不影响之后的标题级别", "# 一\n```\n## 二\n```\n### 三", []string{"5:1 heading-increment"}},
+		{"波浪线代码块", "~~~\n#标题\n~~~\n#标题", []string{"4:2 heading-space"}},
+		{"代码块内的硬换行也是多余空白", "```\ncode  \n```", []string{"2:5 trailing-whitespace"}},
+
+		// CRLF 换行
+		{"CRLF 换行不算行尾空白", "# 一\r\n正文\r\n", nil},
+		{"CRLF 换行的代码块可以闭合", "```\r\ncode\r\n```\r\n", nil},
+		{"CRLF 换行中的问题", "#标题\r\n正文 \r\n", []string{"1:2 heading-space", "2:3 trailing-whitespace"}},
+
+		// 结果按位置排序
+		{"多个问题按位置排序", "正文 \n#标题\n[a]()", []string{"1:3 trailing-whitespace", "2:2 heading-space", "3:1 empty-link"}},
+	}
+
+	linter := NewLinter(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := summarize(linter.Lint(tt.content))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint(%q) = %v，应为 %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintDefaultSeverity(t *testing.T) {
+	tests := []struct {
+		content  string
+		rule     string
+		severity Severity
+	}{
+		{"#标题", "heading-space", SeverityError},
+		{"# 一\n### 三", "heading-increment", SeverityWarning},
+		{"[a](b", "link-syntax", SeverityWarning},
+		{"[a]()", "empty-link", SeverityWarning},
+		{"```", "unclosed-code-fence", SeverityError},
+		{"a ", "trailing-whitespace", SeverityInfo},
+	}
+
+	linter := NewLinter(nil)
+	for _, tt := range tests {
+		diagnostics := linter.Lint(tt.content)
+		if len(diagnostics) != 1 || diagnostics[0].RuleID != tt.rule {
+			t.Errorf("Lint(%q) = %v，应只有 %s", tt.content, summarize(diagnostics), tt.rule)
+			continue
+		}
+		if diagnostics[0].Severity != tt.severity {
+			t.Errorf("%s 的级别为 %s，应为 %s", tt.rule, diagnostics[0].Severity, tt.severity)
+		}
+	}
+}
+
+func TestHasErrors(t *testing.T) {
+	linter := NewLinter(nil)
+	if HasErrors(linter.Lint("a \n[a]()")) {
+		t.Error("只有提示和警告时 HasErrors 应为 false")
+	}
+	if !HasErrors(linter.Lint("#标题")) {
+		t.Error("有错误时 HasErrors 应为 true")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Severity
+		wantErr bool
+	}{
+		{"info", SeverityInfo, false},
+		{"warning", SeverityWarning, false},
+		{"ERROR", SeverityError, false},
+		{"fatal", SeverityInfo, true},
+		{"", SeverityInfo, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSeverity(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSeverity(%q) = %v, %v", tt.name, got, err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule 检查规则
+type Rule struct {
+	ID              string                           // 规则ID，用于配置和输出
+	Description     string                           // 规则说明
+	DefaultSeverity Severity                         // 默认严重级别
+	check           func(doc *document) []Diagnostic // 检查函数，返回的诊断无需填写规则ID和级别
+}
+
+// document 待检查的文档
+type document struct {
+	lines   []string // 所有行
+	inFence []bool   // 每一行是否位于围栏代码块内（含围栏行本身）
+	// unclosedFence 未闭合的围栏代码块起始行号（从1开始），0 表示没有
+	unclosedFence int
+}
+
+// 正则表达式
+var (
+	fenceRegex        = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+	atxHeadingRegex   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]|$)`)
+	missingSpaceRegex = regexp.MustCompile(`^ {0,3}#{1,6}[^#\s]`)
+	linkRegex         = regexp.MustCompile(`\[.*?\]\(.*?\)`)
+	emptyLinkRegex    = regexp.MustCompile(`\[[^\]]*\]\(\s*\)`)
+	trailingRegex     = regexp.MustCompile(`[ \t]+$`)
+)
+
+// newDocument 解析文档的行和代码块范围
+func newDocument(content string) *document {
+	lines := strings.Split(content, "\n")
+	doc := &document{
+		lines:   lines,
+		inFence: make([]bool, len(lines)),
+	}
+
+	fence := "" // 当前代码块的围栏标记
+	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		lines[i] = line
+
+		matches := fenceRegex.FindStringSubmatch(line)
+		if fence == "" {
+			if matches != nil {
+				fence = matches[1]
+				doc.inFence[i] = true
+				doc.unclosedFence = i + 1
+			}
+			continue
+		}
+
+		doc.inFence[i] = true
+		// 闭合围栏必须使用相同字符且长度不少于起始围栏
+		if matches != nil && matches[1][0] == fence[0] && len(matches[1]) >= len(fence) &&
+			strings.TrimSpace(line[len(matches[0]):]) == "" {
+			fence = ""
+			doc.unclosedFence = 0
+		}
+	}
+	return doc
+}
+
+// column 将字节偏移转换为从1开始的字符列号
+func column(line string, byteOffset int) int {
+	return utf8.RuneCountInString(line[:byteOffset]) + 1
+}
+
+// Rules 返回所有内置规则
+func Rules() []*Rule {
+	return []*Rule{
+		{
+			ID:              "heading-space",
+			Description:     "ATX 标题的 # 后面必须有空格",
+			DefaultSeverity: SeverityError,
+			check:           checkHeadingSpace,
+		},
+		{
+			ID:              "heading-increment",
+			Description:     "标题级别每次只能递增一级",
+			DefaultSeverity: SeverityWarning,
+			check:           checkHeadingIncrement,
+		},
+		{
+			ID:              "link-syntax",
+			Description:     "链接必须使用 [文本](地址) 格式",
+			DefaultSeverity: SeverityWarning,
+			check:           checkLinkSyntax,
+		},
+		{
+			ID:              "empty-link",
+			Description:     "链接地址不能为空",
+			DefaultSeverity: SeverityWarning,
+			check:           checkEmptyLink,
+		},
+		{
+			ID:              "unclosed-code-fence",
+			Description:     "围栏代码块必须闭合",
+			DefaultSeverity: SeverityError,
+			check:           checkUnclosedFence,
+		},
+		{
+			ID:              "trailing-whitespace",
+			Description:     "行尾不应有多余空白（两个空格的硬换行除外）",
+			DefaultSeverity: SeverityInfo,
+			check:           checkTrailingWhitespace,
+		},
+	}
+}
+
+// checkHeadingSpace 检查 # 后是否有空格
+func checkHeadingSpace(doc *document) []Diagnostic {
+	var diagnostics []Diagnostic
+	for i, line := range doc.lines {
+		if doc.inFence[i] {
+			continue
+		}
+		if loc := missingSpaceRegex.FindStringIndex(line); loc != nil {
+			// 指向 # 之后的第一个字符，它可能是多字节字符
+			_, size := utf8.DecodeLastRuneInString(line[:loc[1]])
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    i + 1,
+				Column:  column(line, loc[1]-size),
+				Message: "标题格式不正确，# 后面应该有空格",
+			})
+		}
+	}
+	return diagnostics
+}
+
+// checkHeadingIncrement 检查标题级别是否跳级
+func checkHeadingIncrement(doc *document) []Diagnostic {
+	var diagnostics []Diagnostic
+	previous := 0
+	for i, line := range doc.lines {
+		if doc.inFence[i] {
+			continue
+		}
+		matches := atxHeadingRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+
+		level := len(matches[1])
+		if previous > 0 && level > previous+1 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    i + 1,
+				Column:  1,
+				Message: "标题级别跳级，应从 " + strings.Repeat("#", previous+1) + " 开始",
+			})
+		}
+		previous = level
+	}
+	return diagnostics
+}
+
+// checkLinkSyntax 检查链接格式
+func checkLinkSyntax(doc *document) []Diagnostic {
+	var diagnostics []Diagnostic
+	for i, line := range doc.lines {
+		if doc.inFence[i] {
+			continue
+		}
+		index := strings.Index(line, "](")
+		if index >= 0 && !linkRegex.MatchString(line) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    i + 1,
+				Column:  column(line, index),
+				Message: "链接格式可能不正确",
+			})
+		}
+	}
+	return diagnostics
+}
+
+// checkEmptyLink 检查空链接
+func checkEmptyLink(doc *document) []Diagnostic {
+	var diagnostics []Diagnostic
+	for i, line := range doc.lines {
+		if doc.inFence[i] {
+			continue
+		}
+		for _, loc := range emptyLinkRegex.FindAllStringIndex(line, -1) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Line:    i + 1,
+				Column:  column(line, loc[0]),
+				Message: "链接地址为空",
+			})
+		}
+	}
+	return diagnostics
+}
+
+// checkUnclosedFence 检查未闭合的代码块
+func checkUnclosedFence(doc *document) []Diagnostic {
+	if doc.unclosedFence == 0 {
+		return nil
+	}
+	return []Diagnostic{{
+		Line:    doc.unclosedFence,
+		Column:  1,
+		Message: "代码块没有闭合",
+	}}
+}
+
+// checkTrailingWhitespace 检查行尾空白
+func checkTrailingWhitespace(doc *document) []Diagnostic {
+	var diagnostics []Diagnostic
+	for i, line := range doc.lines {
+		loc := trailingRegex.FindStringIndex(line)
+		if loc == nil || strings.TrimSpace(line) == "" {
+			continue
+		}
+		// 两个空格表示硬换行，属于合法写法
+		if line[loc[0]:] == "  " && !doc.inFence[i] {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Line:    i + 1,
+			Column:  column(line, loc[0]),
+			Message: "行尾有多余空白",
+		})
+	}
+	return diagnostics
+}
@@ -0,0 +1,180 @@
+package markdown
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ExportOptions HTML导出选项
+type ExportOptions struct {
+	Title         string // 页面标题，为空时使用第一个一级标题
+	IncludeTOC    bool   // 是否在正文前插入目录
+	SelfContained bool   // 是否把本地图片内嵌为 data URI，生成单个可移植的文件
+	BaseDir       string // 解析相对图片路径的目录（通常为源文件所在目录），只内嵌该目录下的图片，为空时不内嵌
+}
+
+// ExportResult HTML导出结果
+type ExportResult struct {
+	HTML          string   // 完整的HTML页面
+	InlinedImages []string // 已内嵌的本地图片
+	MissingImages []string // 无法读取的本地图片（保留原始引用）
+	SkippedImages []string // 不在 BaseDir 下或不是图片的本地文件（保留原始引用）
+}
+
+// errNotImage 引用的文件不是图片
+var errNotImage = errors.New("不是图片文件")
+
+// imgSrcRegex 匹配 img 标签的 src 属性
+var imgSrcRegex = regexp.MustCompile(`(<img\b[^>]*?\ssrc=")([^"]*)(")`)
+
+// DocumentTitle 获取文档标题，即第一个一级标题的文本
+func (r *Renderer) DocumentTitle(mdContent string) string {
+	for _, entry := range r.ExtractOutline(mdContent) {
+		if entry.Level == 1 {
+			return entry.Title
+		}
+	}
+	return ""
+}
+
+// ExportHTML 按导出选项将Markdown内容渲染为完整的HTML页面
+func (r *Renderer) ExportHTML(mdContent string, opts ExportOptions) *ExportResult {
+	title := opts.Title
+	if title == "" {
+		title = r.DocumentTitle(mdContent)
+	}
+
+	result := &ExportResult{}
+	if opts.IncludeTOC {
+		result.HTML = r.RenderToHTMLWithTOC(mdContent, title)
+	} else {
+		result.HTML = r.RenderToHTMLWithTemplate(mdContent, title)
+	}
+
+	// 样式已经内联在页面模板中，这里只需处理图片
+	if opts.SelfContained {
+		result.HTML = inlineImages(result.HTML, opts.BaseDir, result)
+	}
+
+	return result
+}
+
+// inlineImages 将页面中引用的 baseDir 下的本地图片替换为 base64 data URI，远程图片保持不变
+// 导出的文件可能分享给别人，不能把 baseDir 之外的文件（如 ../../.ssh/id_rsa）或非图片文件写进页面
+func inlineImages(page, baseDir string, result *ExportResult) string {
+	root := ""
+	if baseDir != "" {
+		root = resolvePath(baseDir)
+	}
+
+	return imgSrcRegex.ReplaceAllStringFunc(page, func(match string) string {
+		parts := imgSrcRegex.FindStringSubmatch(match)
+		src := html.UnescapeString(parts[2])
+
+		path, ok := localImagePath(src, baseDir)
+		if !ok {
+			return match
+		}
+		if root == "" {
+			// 未保存的文档没有基准目录
+			result.SkippedImages = append(result.SkippedImages, src)
+			return match
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			result.MissingImages = append(result.MissingImages, src)
+			return match
+		}
+		// 通过符号链接指向 baseDir 之外的文件同样不内嵌
+		if !withinDir(resolvePath(path), root) {
+			result.SkippedImages = append(result.SkippedImages, src)
+			return match
+		}
+
+		dataURI, err := imageDataURI(path)
+		if errors.Is(err, errNotImage) {
+			result.SkippedImages = append(result.SkippedImages, src)
+			return match
+		}
+		if err != nil {
+			result.MissingImages = append(result.MissingImages, src)
+			return match
+		}
+
+		result.InlinedImages = append(result.InlinedImages, src)
+		return parts[1] + dataURI + parts[3]
+	})
+}
+
+// localImagePath 将图片引用解析为本地文件路径，远程地址和 data URI 返回 false
+func localImagePath(src, baseDir string) (string, bool) {
+	if src == "" || strings.HasPrefix(src, "//") || strings.HasPrefix(src, "#") {
+		return "", false
+	}
+
+	u, err := url.Parse(src)
+	if err != nil {
+		// 无法解析为URL的按普通路径处理
+		u = &url.URL{Path: src}
+	}
+	if u.Scheme != "" && u.Scheme != "file" {
+		// Windows 盘符（如 C:）会被解析为单字母的 scheme
+		if len(u.Scheme) != 1 {
+			return "", false
+		}
+		u = &url.URL{Path: src}
+	}
+
+	path := filepath.FromSlash(u.Path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return path, true
+}
+
+// resolvePath 获取绝对路径并解析符号链接，无法解析时返回清理后的绝对路径
+func resolvePath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return path
+}
+
+// withinDir 判断 path 是否位于 dir 之下，两者都应为绝对路径
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// imageDataURI 读取图片文件并编码为 data URI，内容类型不是 image/* 时返回 errNotImage
+func imageDataURI(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(path)))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return "", errNotImage
+	}
+
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(data)), nil
+}
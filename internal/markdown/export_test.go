@@ -0,0 +1,182 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocalImagePath(t *testing.T) {
+	base := filepath.FromSlash("/docs")
+	tests := []struct {
+		src    string
+		want   string
+		wantOK bool
+	}{
+		{"", "", false},
+		{"#anchor", "", false},
+		{"http://example.com/a.png", "", false},
+		{"https://example.com/a.png", "", false},
+		{"//cdn.example.com/a.png", "", false},
+		{"data:image/png;base64,AAAA", "", false},
+		{"mailto:a@example.com", "", false},
+
+		{"a.png", filepath.Join(base, "a.png"), true},
+		{"images/a.png", filepath.Join(base, "images", "a.png"), true},
+		{"../a.png", filepath.FromSlash("/a.png"), true},
+		{"my%20image.png", filepath.Join(base, "my image.png"), true}, // 百分号编码
+		{"a.png?v=2", filepath.Join(base, "a.png"), true},             // 忽略查询参数
+		{"/abs/a.png", filepath.FromSlash("/abs/a.png"), true},
+		{"file:///abs/a.png", filepath.FromSlash("/abs/a.png"), true},
+		{"file:///abs/my%20image.png", filepath.FromSlash("/abs/my image.png"), true},
+	}
+
+	for _, tt := range tests {
+		got, ok := localImagePath(tt.src, base)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("localImagePath(%q) = %q, %v，应为 %q, %v", tt.src, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// Windows 盘符不能被当作 URL 的 scheme
+	path, ok := localImagePath("C:/images/a.png", base)
+	if !ok || !strings.HasSuffix(path, filepath.FromSlash("C:/images/a.png")) {
+		t.Errorf("localImagePath(%q) = %q, %v，应作为本地路径处理", "C:/images/a.png", path, ok)
+	}
+}
+
+// pngData 最小的 PNG 文件头，足以识别内容类型
+var pngData = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+func TestInlineImages(t *testing.T) {
+	root := t.TempDir()
+	base := filepath.Join(root, "docs")
+	for _, dir := range []string{base, filepath.Join(base, "images")} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string][]byte{
+		filepath.Join(base, "a.png"):             pngData,
+		filepath.Join(base, "images", "b c.png"): pngData,
+		filepath.Join(base, "noext"):             pngData, // 没有扩展名时按内容识别
+		filepath.Join(base, "notes.txt"):         []byte("不是图片"),
+		filepath.Join(root, "outside.png"):       pngData,
+		filepath.Join(root, "secret.txt"):        []byte("密码"),
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hasSymlink := os.Symlink(filepath.Join(root, "outside.png"), filepath.Join(base, "link.png")) == nil
+
+	// 直接构造页面：渲染时的 HTML 清理会去掉 data: 和 file:// 地址，这里确认内嵌本身也不处理它们
+	srcs := []string{
+		"a.png",
+		"images/b%20c.png",
+		"noext",
+		filepath.ToSlash(filepath.Join(base, "a.png")),
+		"file://" + filepath.ToSlash(filepath.Join(base, "a.png")),
+		"notes.txt",
+		"../outside.png",
+		"../secret.txt",
+		"file://" + filepath.ToSlash(filepath.Join(root, "outside.png")),
+		"missing.png",
+		"https://example.com/remote.png",
+		"data:image/png;base64,AAAA",
+	}
+	if hasSymlink {
+		srcs = append(srcs, "link.png")
+	}
+	var page strings.Builder
+	for _, src := range srcs {
+		page.WriteString(`<p><img alt="" src="` + src + `"></p>` + "\n")
+	}
+
+	result := &ExportResult{}
+	html := inlineImages(page.String(), base, result)
+
+	wantInlined := srcs[:5]
+	wantMissing := []string{"missing.png"}
+	wantSkipped := []string{"notes.txt", "../outside.png", "../secret.txt", "file://" + filepath.ToSlash(filepath.Join(root, "outside.png"))}
+	if hasSymlink {
+		wantSkipped = append(wantSkipped, "link.png")
+	}
+
+	if !reflect.DeepEqual(result.InlinedImages, wantInlined) {
+		t.Errorf("内嵌的图片为 %q，应为 %q", result.InlinedImages, wantInlined)
+	}
+	if !reflect.DeepEqual(result.MissingImages, wantMissing) {
+		t.Errorf("无法读取的图片为 %q，应为 %q", result.MissingImages, wantMissing)
+	}
+	if !reflect.DeepEqual(result.SkippedImages, wantSkipped) {
+		t.Errorf("跳过的文件为 %q，应为 %q", result.SkippedImages, wantSkipped)
+	}
+
+	if got := strings.Count(html, "data:image/png;base64,iVBORw0KGgo"); got != len(wantInlined) {
+		t.Errorf("页面中有 %d 张内嵌图片，应为 %d", got, len(wantInlined))
+	}
+	for _, src := range srcs[5:] {
+		if !strings.Contains(html, `src="`+src+`"`) {
+			t.Errorf("页面中没有保留原始引用 %s", src)
+		}
+	}
+}
+
+func TestExportHTMLSelfContained(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a b.png"), pngData, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "![](a%20b.png)\n\n![](missing.png)\n\n![](https://example.com/a.png)"
+	result := NewRenderer().ExportHTML(content, ExportOptions{SelfContained: true, BaseDir: dir})
+
+	if want := []string{"a%20b.png"}; !reflect.DeepEqual(result.InlinedImages, want) {
+		t.Errorf("内嵌的图片为 %q，应为 %q", result.InlinedImages, want)
+	}
+	if want := []string{"missing.png"}; !reflect.DeepEqual(result.MissingImages, want) {
+		t.Errorf("无法读取的图片为 %q，应为 %q", result.MissingImages, want)
+	}
+	if !strings.Contains(result.HTML, `src="https://example.com/a.png"`) {
+		t.Error("远程图片的引用被修改")
+	}
+}
+
+func TestExportHTMLWithoutBaseDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.png")
+	if err := os.WriteFile(path, pngData, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未保存的文档没有基准目录，绝对路径的图片也不内嵌
+	content := "![](a.png)\n\n![](" + filepath.ToSlash(path) + ")\n\n![](https://example.com/a.png)"
+	result := NewRenderer().ExportHTML(content, ExportOptions{SelfContained: true})
+
+	if len(result.InlinedImages) != 0 || len(result.MissingImages) != 0 {
+		t.Errorf("内嵌了 %q，无法读取 %q，都应为空", result.InlinedImages, result.MissingImages)
+	}
+	want := []string{"a.png", filepath.ToSlash(path)}
+	if !reflect.DeepEqual(result.SkippedImages, want) {
+		t.Errorf("跳过的文件为 %q，应为 %q", result.SkippedImages, want)
+	}
+}
+
+func TestExportHTMLNotSelfContained(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.png"), pngData, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := NewRenderer().ExportHTML("![](a.png)", ExportOptions{BaseDir: dir})
+	if len(result.InlinedImages)+len(result.MissingImages)+len(result.SkippedImages) != 0 {
+		t.Errorf("不内嵌时不应处理图片：%+v", result)
+	}
+	if !strings.Contains(result.HTML, `src="a.png"`) {
+		t.Error("页面中没有保留原始引用")
+	}
+}
@@ -0,0 +1,106 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/parser"
+
+	"markup/internal/core"
+)
+
+// ExtractOutline 从Markdown内容中提取大纲
+// 大纲基于解析后的语法树生成，因此代码块中的 # 注释不会被识别为标题，
+// 同时支持 Setext 风格（=== / ---）标题和闭合的 # 序列
+func (r *Renderer) ExtractOutline(mdContent string) []core.OutlineEntry {
+	input := parser.NormalizeNewlines([]byte(mdContent))
+
+	// 完整解析：得到标题级别、标题ID以及行内内容
+	doc := r.parse(string(input))
+	headings := collectHeadings(doc)
+
+	// 只做块级解析：此时标题节点仍保留指向源文本的 Content，用于定位行号
+	blockParser := r.newParser()
+	blockParser.Block(input)
+	sources := collectHeadings(blockParser.Doc)
+
+	var outline []core.OutlineEntry
+	searchFrom := 0
+	for i, heading := range headings {
+		line := 0
+		if len(sources) == len(headings) {
+			offset := headingOffset(input, sources[i].Content, searchFrom)
+			if offset >= 0 {
+				line = bytes.Count(input[:offset], []byte("\n")) + 1
+				searchFrom = offset + len(sources[i].Content)
+			}
+		}
+
+		outline = append(outline, core.OutlineEntry{
+			Title: headingText(heading),
+			Level: heading.Level,
+			Line:  line,
+			ID:    heading.HeadingID,
+		})
+	}
+
+	return outline
+}
+
+// collectHeadings 按文档顺序收集所有标题节点
+func collectHeadings(doc ast.Node) []*ast.Heading {
+	var headings []*ast.Heading
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		if heading, ok := node.(*ast.Heading); ok && entering {
+			headings = append(headings, heading)
+		}
+		return ast.GoToNext
+	})
+	return headings
+}
+
+// headingOffset 获取标题内容在源文本中的字节偏移，找不到时返回 -1
+func headingOffset(input, content []byte, searchFrom int) int {
+	if len(content) == 0 {
+		return -1
+	}
+
+	// 大多数标题的 Content 直接引用源文本，可以精确计算偏移
+	offset := cap(input) - cap(content)
+	if offset >= 0 && offset+len(content) <= len(input) && &input[offset] == &content[0] {
+		return offset
+	}
+
+	// 引用块、列表中的标题是解析器复制出来的，按文本顺序查找
+	if searchFrom > len(input) {
+		return -1
+	}
+	index := bytes.Index(input[searchFrom:], content)
+	if index < 0 {
+		return -1
+	}
+	return searchFrom + index
+}
+
+// headingText 获取标题的纯文本，去除强调、链接、代码等行内标记
+func headingText(heading *ast.Heading) string {
+	var buf strings.Builder
+	ast.WalkFunc(heading, func(node ast.Node, entering bool) ast.WalkStatus {
+		if !entering {
+			return ast.GoToNext
+		}
+		switch n := node.(type) {
+		case *ast.Text:
+			buf.Write(n.Literal)
+		case *ast.Code:
+			buf.Write(n.Literal)
+		case *ast.Softbreak, *ast.Hardbreak:
+			buf.WriteByte(' ')
+		case *ast.HTMLSpan:
+			// 忽略行内HTML标签
+		}
+		return ast.GoToNext
+	})
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
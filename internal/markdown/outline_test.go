@@ -0,0 +1,136 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+
+	"markup/internal/core"
+)
+
+func TestExtractOutline(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []core.OutlineEntry
+	}{
+		{"没有标题", "正文\n\n更多正文", nil},
+		{
+			"ATX 标题",
+			"# 一\n\n正文\n\n## 二\n\n### 三",
+			[]core.OutlineEntry{
+				{Title: "一", Level: 1, Line: 1, ID: "一"},
+				{Title: "二", Level: 2, Line: 5, ID: "二"},
+				{Title: "三", Level: 3, Line: 7, ID: "三"},
+			},
+		},
+		{
+			"闭合的 # 序列",
+			"# 标题 #\n\n## 二级 ##",
+			[]core.OutlineEntry{
+				{Title: "标题", Level: 1, Line: 1, ID: "标题"},
+				{Title: "二级", Level: 2, Line: 3, ID: "二级"},
+			},
+		},
+		{
+			"Setext 标题",
+			"一级\n===\n\n二级\n---\n\n# 三",
+			[]core.OutlineEntry{
+				{Title: "一级", Level: 1, Line: 1, ID: "一级"},
+				{Title: "二级", Level: 2, Line: 4, ID: "二级"},
+				{Title: "三", Level: 1, Line: 7, ID: "三"},
+			},
+		},
+		{
+			"围栏代码块中的 #",
+			"# 一\n\n```\n# 注释\n```\n\n~~~sh\n## 注释\n~~~\n\n# 二",
+			[]core.OutlineEntry{
+				{Title: "一", Level: 1, Line: 1, ID: "一"},
+				{Title: "二", Level: 1, Line: 11, ID: "二"},
+			},
+		},
+		{
+			"缩进代码块中的 #",
+			"# 一\n\n    # 注释\n    ## 注释\n\n# 二",
+			[]core.OutlineEntry{
+				{Title: "一", Level: 1, Line: 1, ID: "一"},
+				{Title: "二", Level: 1, Line: 6, ID: "二"},
+			},
+		},
+		{
+			"CRLF 换行",
+			"# 一\r\n\r\n正文\r\n\r\n## 二\r\n",
+			[]core.OutlineEntry{
+				{Title: "一", Level: 1, Line: 1, ID: "一"},
+				{Title: "二", Level: 2, Line: 5, ID: "二"},
+			},
+		},
+		{
+			"引用块中的标题",
+			"# 一\n\n> 引用\n>\n> ## 引用中的标题\n\n# 二",
+			[]core.OutlineEntry{
+				{Title: "一", Level: 1, Line: 1, ID: "一"},
+				{Title: "引用中的标题", Level: 2, Line: 5, ID: "引用中的标题"},
+				{Title: "二", Level: 1, Line: 7, ID: "二"},
+			},
+		},
+		{
+			// 解析器不支持列表项中的标题，预览中同样显示为普通文本
+			"列表项中的 #",
+			"# 一\n\n- ### 列表\n1. ## 列表\n\n# 二",
+			[]core.OutlineEntry{
+				{Title: "一", Level: 1, Line: 1, ID: "一"},
+				{Title: "二", Level: 1, Line: 6, ID: "二"},
+			},
+		},
+		{
+			"重复标题",
+			"# 用法\n\n## 用法\n\n> ## 用法\n\n## 用法",
+			[]core.OutlineEntry{
+				{Title: "用法", Level: 1, Line: 1, ID: "用法"},
+				{Title: "用法", Level: 2, Line: 3, ID: "用法-1"},
+				{Title: "用法", Level: 2, Line: 5, ID: "用法-2"},
+				{Title: "用法", Level: 2, Line: 7, ID: "用法-3"},
+			},
+		},
+		{
+			"行内格式",
+			"# **粗体** 与 `代码` 和 [链接](http://example.com)",
+			[]core.OutlineEntry{
+				{Title: "粗体 与 代码 和 链接", Level: 1, Line: 1, ID: "粗体-与-代码-和-链接"},
+			},
+		},
+	}
+
+	r := NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := r.ExtractOutline(tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractOutline(%q) =\n%+v\n应为\n%+v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadingOffset(t *testing.T) {
+	input := []byte("# 一\n\n> # 一\n\n# 二")
+
+	tests := []struct {
+		name       string
+		content    []byte
+		searchFrom int
+		want       int
+	}{
+		{"引用源文本", input[2:5], 0, 2},
+		{"复制的内容从 searchFrom 开始查找", []byte("一"), 5, 11},
+		{"找不到", []byte("三"), 0, -1},
+		{"空内容", nil, 0, -1},
+		{"searchFrom 超出范围", []byte("一"), len(input) + 1, -1},
+	}
+
+	for _, tt := range tests {
+		if got := headingOffset(input, tt.content, tt.searchFrom); got != tt.want {
+			t.Errorf("%s：headingOffset() = %d，应为 %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -5,47 +5,65 @@ import (
 	"fmt"
 	"html/template"
 	"regexp"
-	"strings"
 
 	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
 	"github.com/microcosm-cc/bluemonday"
 
 	"markup/internal/core"
+	"markup/internal/lint"
 )
 
 // Renderer Markdown渲染器
 type Renderer struct {
-	parser    *parser.Parser     // Markdown解析器
-	htmlFlags html.Flags         // HTML渲染标志
-	policy    *bluemonday.Policy // HTML清理策略
+	extensions parser.Extensions  // Markdown解析扩展
+	htmlFlags  html.Flags         // HTML渲染标志
+	policy     *bluemonday.Policy // HTML清理策略
 }
 
 // NewRenderer 创建新的Markdown渲染器
 func NewRenderer() *Renderer {
 	// 配置Markdown解析器
-	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
-
-	p := parser.NewWithExtensions(extensions)
+	// 标题ID由 assignHeadingIDs 统一生成，不使用 parser.AutoHeadingIDs
+	extensions := parser.CommonExtensions | parser.NoEmptyLineBeforeBlock
 
 	// 配置HTML渲染选项
 	htmlFlags := html.CommonFlags | html.HrefTargetBlank
 
 	// 创建HTML清理策略
+	// 默认策略只允许ASCII字符的id，这里放开标题的id以保留中文等锚点
 	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("id").Matching(headingIDRegex).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
 
 	return &Renderer{
-		parser:    p,
-		htmlFlags: htmlFlags,
-		policy:    policy,
+		extensions: extensions,
+		htmlFlags:  htmlFlags,
+		policy:     policy,
 	}
 }
 
+// headingIDRegex 允许的标题ID（各语言的字母、数字、连字符和下划线）
+var headingIDRegex = regexp.MustCompile(`^[\p{L}\p{N}\p{Mn}_\-]+$`)
+
+// newParser 创建Markdown解析器
+// gomarkdown 的解析器会保存解析状态，不能重复使用，每次解析都需要新建
+func (r *Renderer) newParser() *parser.Parser {
+	return parser.NewWithExtensions(r.extensions)
+}
+
+// parse 解析Markdown并为标题分配ID
+func (r *Renderer) parse(mdContent string) ast.Node {
+	doc := r.newParser().Parse([]byte(mdContent))
+	assignHeadingIDs(doc)
+	return doc
+}
+
 // RenderToHTML 将Markdown内容渲染为HTML
 func (r *Renderer) RenderToHTML(mdContent string) string {
 	// 解析Markdown
-	doc := r.parser.Parse([]byte(mdContent))
+	doc := r.parse(mdContent)
 
 	// 创建HTML渲染器
 	renderer := html.NewRenderer(html.RendererOptions{Flags: r.htmlFlags})
@@ -61,9 +79,11 @@ func (r *Renderer) RenderToHTML(mdContent string) string {
 
 // RenderToHTMLWithTemplate 将Markdown内容渲染为带模板的完整HTML页面
 func (r *Renderer) RenderToHTMLWithTemplate(mdContent, title string) string {
-	// 获取HTML内容
-	htmlContent := r.RenderToHTML(mdContent)
+	return r.renderPage(r.RenderToHTML(mdContent), title)
+}
 
+// renderPage 将已渲染的HTML内容套入页面模板
+func (r *Renderer) renderPage(htmlContent, title string) string {
 	// HTML模板
 	tmpl := `<!DOCTYPE html>
 <html lang="zh-CN">
@@ -188,39 +208,6 @@ func (r *Renderer) RenderToRichText(mdContent string) string {
 	return mdContent
 }
 
-// ExtractOutline 从Markdown内容中提取大纲
-func (r *Renderer) ExtractOutline(mdContent string) []core.OutlineEntry {
-	var outline []core.OutlineEntry
-
-	// 按行分割内容
-	lines := strings.Split(mdContent, "\n")
-
-	// 正则表达式匹配标题
-	headerRegex := regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
-
-	for lineNum, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// 匹配标题行
-		matches := headerRegex.FindStringSubmatch(line)
-		if len(matches) == 3 {
-			level := len(matches[1]) // #的数量就是标题级别
-			title := strings.TrimSpace(matches[2])
-
-			// 创建大纲条目
-			entry := core.OutlineEntry{
-				Title: title,
-				Level: level,
-				Line:  lineNum + 1, // 行号从1开始
-			}
-
-			outline = append(outline, entry)
-		}
-	}
-
-	return outline
-}
-
 // GenerateTableOfContents 生成目录HTML
 func (r *Renderer) GenerateTableOfContents(outline []core.OutlineEntry) string {
 	if len(outline) == 0 {
@@ -231,6 +218,9 @@ func (r *Renderer) GenerateTableOfContents(outline []core.OutlineEntry) string {
 	buf.WriteString(`<div class="toc">`)
 	buf.WriteString(`<h3>目录</h3>`)
 
+	// 大纲中没有ID时（如手动构造的大纲）按相同规则生成
+	slugger := NewSlugger()
+
 	currentLevel := 0
 	for _, entry := range outline {
 		// 处理级别变化
@@ -246,15 +236,15 @@ func (r *Renderer) GenerateTableOfContents(outline []core.OutlineEntry) string {
 			}
 		}
 
-		// 生成锚点ID（移除特殊字符，用连字符替换空格）
-		anchorID := strings.ToLower(entry.Title)
-		anchorID = regexp.MustCompile(`[^\w\s-]`).ReplaceAllString(anchorID, "")
-		anchorID = regexp.MustCompile(`\s+`).ReplaceAllString(anchorID, "-")
-		anchorID = strings.Trim(anchorID, "-")
+		// 锚点ID与渲染出的标题ID一致
+		anchorID := entry.ID
+		if anchorID == "" {
+			anchorID = slugger.Slug(entry.Title)
+		}
 
 		// 添加目录项
 		buf.WriteString(`<li><a href="#`)
-		buf.WriteString(anchorID)
+		buf.WriteString(template.HTMLEscapeString(anchorID))
 		buf.WriteString(`">`)
 		buf.WriteString(template.HTMLEscapeString(entry.Title))
 		buf.WriteString(`</a></li>`)
@@ -285,35 +275,16 @@ func (r *Renderer) RenderToHTMLWithTOC(mdContent, title string) string {
 	// 将目录插入到内容前面
 	contentWithTOC := toc + htmlContent
 
-	// 使用模板渲染完整页面
-	return r.RenderToHTMLWithTemplate(contentWithTOC, title)
+	// 使用模板渲染完整页面（内容已是HTML，不能再次按Markdown渲染）
+	return r.renderPage(contentWithTOC, title)
 }
 
-// ValidateMarkdown 验证Markdown语法
+// ValidateMarkdown 验证Markdown语法，使用默认规则返回可读的警告列表
 func (r *Renderer) ValidateMarkdown(mdContent string) []string {
 	var warnings []string
 
-	// 检查常见的Markdown语法问题
-	lines := strings.Split(mdContent, "\n")
-
-	for lineNum, line := range lines {
-		lineNum++ // 行号从1开始
-
-		// 检查标题格式
-		if strings.HasPrefix(line, "#") {
-			if !regexp.MustCompile(`^#{1,6}\s+.+`).MatchString(line) {
-				warnings = append(warnings,
-					fmt.Sprintf("第%d行: 标题格式不正确，# 后面应该有空格", lineNum))
-			}
-		}
-
-		// 检查链接格式
-		if strings.Contains(line, "](") {
-			if !regexp.MustCompile(`\[.*?\]\(.*?\)`).MatchString(line) {
-				warnings = append(warnings,
-					fmt.Sprintf("第%d行: 链接格式可能不正确", lineNum))
-			}
-		}
+	for _, d := range lint.NewLinter(nil).Lint(mdContent) {
+		warnings = append(warnings, fmt.Sprintf("第%d行: %s", d.Line, d.Message))
 	}
 
 	return warnings
@@ -0,0 +1,77 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gomarkdown/markdown/ast"
+)
+
+// Slugger 标题ID生成器，为同一文档中的标题生成唯一的锚点ID
+// 渲染HTML和生成目录使用同一套规则，保证目录链接与标题ID一致
+type Slugger struct {
+	used map[string]bool // 已使用的ID
+}
+
+// NewSlugger 创建新的标题ID生成器，每个文档使用一个实例
+func NewSlugger() *Slugger {
+	return &Slugger{used: make(map[string]bool)}
+}
+
+// Reserve 登记文档中显式指定的ID（如 {#custom-id}），避免自动生成的ID与之重复
+func (s *Slugger) Reserve(id string) {
+	s.used[id] = true
+}
+
+// Slug 为标题生成唯一ID，重复时依次追加 -1、-2 等后缀
+func (s *Slugger) Slug(title string) string {
+	base := Slugify(title)
+	if base == "" {
+		base = "section"
+	}
+
+	id := base
+	for i := 1; s.used[id]; i++ {
+		id = base + "-" + strconv.Itoa(i)
+	}
+	s.used[id] = true
+	return id
+}
+
+// Slugify 将标题文本转换为锚点：保留各语言的字母和数字（包括中文），
+// 转为小写，空白替换为连字符，去除其他符号
+func Slugify(text string) string {
+	var buf strings.Builder
+	pendingDash := false
+	for _, r := range strings.TrimSpace(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r) || r == '_':
+			if pendingDash && buf.Len() > 0 {
+				buf.WriteByte('-')
+			}
+			pendingDash = false
+			buf.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r) || r == '-':
+			pendingDash = true
+		}
+	}
+	return buf.String()
+}
+
+// assignHeadingIDs 按文档顺序为标题分配ID，已显式指定ID的标题保持不变
+func assignHeadingIDs(doc ast.Node) {
+	headings := collectHeadings(doc)
+
+	slugger := NewSlugger()
+	for _, heading := range headings {
+		if heading.HeadingID != "" {
+			slugger.Reserve(heading.HeadingID)
+		}
+	}
+	for _, heading := range headings {
+		if heading.HeadingID == "" {
+			heading.HeadingID = slugger.Slug(headingText(heading))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package markdown
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"markup/internal/core"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello World", "hello-world"},
+		{"  Hello   World  ", "hello-world"},
+		{"Hello, World!", "hello-world"},
+		{"C++ & Go", "c-go"},
+		{"snake_case 标题", "snake_case-标题"},
+		{"already-dashed -- title", "already-dashed-title"},
+		{"- 前后的连字符 -", "前后的连字符"},
+		{"Version 2.0", "version-20"},
+		{"", ""},
+		{"!!!", ""},
+
+		// Unicode：保留各语言的字母和数字，转为小写
+		{"快速开始", "快速开始"},
+		{"第 1 章：简介", "第-1-章简介"},
+		{"Überblick Änderungen", "überblick-änderungen"},
+		{"Ελληνικά Κείμενα", "ελληνικά-κείμενα"},
+		{"日本語のテキスト", "日本語のテキスト"},
+		{"한국어 제목", "한국어-제목"},
+		{"café́", "café́"}, // 组合附加符号（Mn）保留
+		{"emoji 🎉 标题", "emoji-标题"},
+		{"全角　空格", "全角-空格"},
+		{"١٢٣ عربي", "١٢٣-عربي"},
+	}
+
+	for _, tt := range tests {
+		if got := Slugify(tt.text); got != tt.want {
+			t.Errorf("Slugify(%q) = %q，应为 %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSlugger(t *testing.T) {
+	tests := []struct {
+		name     string
+		reserved []string
+		titles   []string
+		want     []string
+	}{
+		{"不重复", nil, []string{"一", "二"}, []string{"一", "二"}},
+		{"重复时追加后缀", nil, []string{"简介", "简介", "简介"}, []string{"简介", "简介-1", "简介-2"}},
+		{"大小写和符号不同的标题也会重复", nil, []string{"Hello World", "hello world!", "HELLO-WORLD"}, []string{"hello-world", "hello-world-1", "hello-world-2"}},
+		{"没有文字的标题", nil, []string{"!!!", "", "???"}, []string{"section", "section-1", "section-2"}},
+		{"后缀与已有ID冲突", nil, []string{"a-1", "a", "a"}, []string{"a-1", "a", "a-2"}},
+		{"避开显式指定的ID", []string{"intro", "intro-1"}, []string{"Intro", "Intro"}, []string{"intro-2", "intro-3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slugger := NewSlugger()
+			for _, id := range tt.reserved {
+				slugger.Reserve(id)
+			}
+			var got []string
+			for _, title := range tt.titles {
+				got = append(got, slugger.Slug(title))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Slug(%q) = %q，应为 %q", tt.titles, got, tt.want)
+			}
+		})
+	}
+}
+
+// 匹配渲染结果中的标题ID和目录链接
+var (
+	headingIDPattern = regexp.MustCompile(`<h[1-6] id="([^"]*)"`)
+	tocAnchorPattern = regexp.MustCompile(`<li><a href="#([^"]*)"`)
+)
+
+func TestHeadingIDsMatchTOC(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"中文标题", "# 快速开始\n\n## 安装\n\n## 配置", []string{"快速开始", "安装", "配置"}},
+		{"重复标题", "# 简介\n\n## 用法\n\n# 进阶\n\n## 用法\n\n## 用法", []string{"简介", "用法", "进阶", "用法-1", "用法-2"}},
+		{"Setext 标题和行内格式", "Hello *World*\n===\n\n## `code` 与 [链接](http://example.com)", []string{"hello-world", "code-与-链接"}},
+		{"显式指定的ID", "# 简介 {#intro}\n\n# Intro\n\n# 简介", []string{"intro", "intro-1", "简介"}},
+		{"没有文字的标题", "# !!!\n\n# ???", []string{"section", "section-1"}},
+		{"代码块中的 # 不是标题", "# 一\n\n```\n# 一\n```\n\n# 一", []string{"一", "一-1"}},
+		{"Unicode 标题", "# Überblick\n\n# Ελληνικά\n\n# 한국어 제목", []string{"überblick", "ελληνικά", "한국어-제목"}},
+	}
+
+	r := NewRenderer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := r.RenderToHTMLWithTOC(tt.content, "测试")
+
+			var headingIDs, anchors []string
+			for _, match := range headingIDPattern.FindAllStringSubmatch(page, -1) {
+				headingIDs = append(headingIDs, match[1])
+			}
+			for _, match := range tocAnchorPattern.FindAllStringSubmatch(page, -1) {
+				anchors = append(anchors, match[1])
+			}
+
+			if !reflect.DeepEqual(headingIDs, tt.want) {
+				t.Errorf("标题ID为 %q，应为 %q", headingIDs, tt.want)
+			}
+			if !reflect.DeepEqual(anchors, headingIDs) {
+				t.Errorf("目录链接 %q 与标题ID %q 不一致", anchors, headingIDs)
+			}
+
+			// 大纲中的ID也与标题一致
+			var outlineIDs []string
+			for _, entry := range r.ExtractOutline(tt.content) {
+				outlineIDs = append(outlineIDs, entry.ID)
+			}
+			if !reflect.DeepEqual(outlineIDs, headingIDs) {
+				t.Errorf("大纲ID %q 与标题ID %q 不一致", outlineIDs, headingIDs)
+			}
+		})
+	}
+}
+
+func TestTableOfContentsWithoutIDs(t *testing.T) {
+	// 手动构造的大纲没有ID时按相同规则生成锚点
+	outline := []core.OutlineEntry{
+		{Title: "简介", Level: 1},
+		{Title: "用法", Level: 2},
+		{Title: "用法", Level: 2},
+		{Title: "<script>", Level: 2},
+	}
+	toc := NewRenderer().GenerateTableOfContents(outline)
+
+	var anchors []string
+	for _, match := range tocAnchorPattern.FindAllStringSubmatch(toc, -1) {
+		anchors = append(anchors, match[1])
+	}
+	want := []string{"简介", "用法", "用法-1", "script"}
+	if !reflect.DeepEqual(anchors, want) {
+		t.Errorf("目录链接为 %q，应为 %q", anchors, want)
+	}
+	if regexp.MustCompile(`<script>`).MatchString(toc) {
+		t.Error("目录中的标题文本没有转义")
+	}
+}
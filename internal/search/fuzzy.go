@@ -0,0 +1,101 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 模糊匹配的评分
+const (
+	fuzzyMatchScore       = 16 // 每个匹配的字符
+	fuzzyConsecutiveBonus = 24 // 与上一个匹配的字符相邻
+	fuzzyBoundaryBonus    = 20 // 位于单词或路径段的开头
+	fuzzyBaseNameBonus    = 8  // 位于路径的最后一段（文件名）中
+	fuzzyGapPenalty       = 1  // 两个匹配的字符之间每隔一个字符
+)
+
+// FuzzyScore 判断 pattern 中的字符（忽略大小写和空格）是否按顺序出现在 text 中，并计算匹配得分
+// 得分越高表示越符合：连续的字符、单词开头和文件名中的匹配得分更高，间隔越大得分越低
+// pattern 为空时总是匹配，得分为 0
+func FuzzyScore(pattern, text string) (int, bool) {
+	var query []rune
+	for _, r := range strings.ToLower(pattern) {
+		if !unicode.IsSpace(r) {
+			query = append(query, r)
+		}
+	}
+	if len(query) == 0 {
+		return 0, true
+	}
+
+	runes := []rune(text)
+	lower := []rune(strings.ToLower(text))
+	if len(lower) != len(runes) {
+		// 个别字符转换为小写后长度不同，退回逐个转换
+		lower = make([]rune, len(runes))
+		for i, r := range runes {
+			lower[i] = unicode.ToLower(r)
+		}
+	}
+	baseStart := strings.LastIndexAny(text, `/\`) + 1
+	baseStart = len([]rune(text[:baseStart]))
+
+	// 从第一个字符每一次出现的位置开始贪心匹配，取得分最高的一次
+	best, matched := 0, false
+	for start := range lower {
+		if lower[start] != query[0] {
+			continue
+		}
+		if score, ok := fuzzyFrom(query, runes, lower, start, baseStart); ok && (!matched || score > best) {
+			best, matched = score, true
+		}
+	}
+	return best, matched
+}
+
+// fuzzyFrom 从 start 开始按顺序匹配 query 中的字符并计算得分
+func fuzzyFrom(query, runes, lower []rune, start, baseStart int) (int, bool) {
+	score := 0
+	previous := -1
+	q := 0
+	for i := start; i < len(lower) && q < len(query); i++ {
+		if lower[i] != query[q] {
+			continue
+		}
+
+		score += fuzzyMatchScore
+		if previous >= 0 {
+			if i == previous+1 {
+				score += fuzzyConsecutiveBonus
+			} else {
+				score -= (i - previous - 1) * fuzzyGapPenalty
+			}
+		}
+		if isBoundary(runes, i) {
+			score += fuzzyBoundaryBonus
+		}
+		if i >= baseStart {
+			score += fuzzyBaseNameBonus
+		}
+		previous = i
+		q++
+	}
+	if q < len(query) {
+		return 0, false
+	}
+	// 文本越短越符合
+	return score - len(runes)/4, true
+}
+
+// isBoundary 判断第 i 个字符是否位于单词或路径段的开头（包括驼峰命名中的大写字母）
+func isBoundary(runes []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	previous := runes[i-1]
+	switch previous {
+	case '/', '\\', '_', '-', '.', ' ':
+		return true
+	}
+	return unicode.IsUpper(runes[i]) && unicode.IsLower(previous)
+}
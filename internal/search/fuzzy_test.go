@@ -0,0 +1,96 @@
+package search
+
+import "testing"
+
+func TestFuzzyScoreMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    bool
+	}{
+		{"", "任意文本", true},
+		{"   ", "任意文本", true},
+		{"rdme", "README.md", true},
+		{"RdMe", "readme.md", true},
+		{"read me", "README.md", true}, // 忽略空格
+		{"dr", "docs/readme.md", true},
+		{"md", "docs/readme.md", true},
+		{"emdaer", "README.md", false}, // 顺序不对
+		{"readmex", "README.md", false},
+		{"保存", "保存文件", true},
+		{"存保", "保存文件", false},
+		{"wj", "保存文件", false},
+		{"x", "", false},
+	}
+
+	for _, tt := range tests {
+		if _, got := FuzzyScore(tt.pattern, tt.text); got != tt.want {
+			t.Errorf("FuzzyScore(%q, %q) 匹配为 %v，应为 %v", tt.pattern, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyScoreRanking(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		texts   []string // 按得分从高到低排列
+	}{
+		{
+			"连续的字符优先",
+			"read",
+			[]string{"read.md", "r-e-a-d.md", "rxexaxd.md"},
+		},
+		{
+			"文件名中的匹配优先于目录中的",
+			"guide",
+			[]string{"docs/guide.md", "guide/index.md"},
+		},
+		{
+			"单词开头优先",
+			"fo",
+			[]string{"notes/file_open.md", "notes/info.md"},
+		},
+		{
+			"驼峰命名的大写字母算作单词开头",
+			"ot",
+			[]string{"openTab.go", "footnote.go"},
+		},
+		{
+			"间隔越小越优先",
+			"ab",
+			[]string{"a_b.md", "a__b.md", "a____b.md"},
+		},
+		{
+			"文本越短越优先",
+			"todo",
+			[]string{"todo.md", "todo-list-archive.md"},
+		},
+		{
+			"从得分最高的位置开始匹配",
+			"set",
+			[]string{"s_e_t_set", "s_e_t_sxt"},
+		},
+		{
+			"命令标题",
+			"保存",
+			[]string{"保存", "另存为文件并保存"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := 0
+			for i, text := range tt.texts {
+				score, ok := FuzzyScore(tt.pattern, text)
+				if !ok {
+					t.Fatalf("FuzzyScore(%q, %q) 不匹配", tt.pattern, text)
+				}
+				if i > 0 && score >= previous {
+					t.Errorf("%q 的得分为 %d，应低于 %q 的 %d", text, score, tt.texts[i-1], previous)
+				}
+				previous = score
+			}
+		})
+	}
+}
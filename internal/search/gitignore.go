@@ -0,0 +1,100 @@
+package search
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule .gitignore 中的一条规则
+type ignoreRule struct {
+	base     string // .gitignore 所在目录相对于工作区根目录的路径，根目录为空
+	pattern  string // 去掉前缀 ! 和首尾 / 后的模式
+	negate   bool   // 以 ! 开头，重新包含之前被忽略的路径
+	dirOnly  bool   // 以 / 结尾，只匹配目录
+	anchored bool   // 包含 /，相对于 .gitignore 所在目录匹配
+}
+
+// ignoreRules 从工作区根目录到当前目录的所有 .gitignore 规则，后面的规则优先
+type ignoreRules []ignoreRule
+
+// withFile 读取目录中的 .gitignore 并返回追加了其中规则的新列表，文件不存在时返回原列表
+// dir 为目录的绝对路径，base 为其相对于工作区根目录的路径
+func (r ignoreRules) withFile(dir, base string) ignoreRules {
+	file, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return r
+	}
+	defer file.Close()
+
+	// 复制一份，避免与兄弟目录共用底层数组
+	rules := append(ignoreRules(nil), r...)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text(), base); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseIgnoreRule 解析 .gitignore 中的一行，空行和注释返回 false
+func parseIgnoreRule(line, base string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	// \# 和 \! 表示以这两个字符开头的文件名
+	line = strings.TrimPrefix(line, "\\")
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimLeft(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	rule.pattern = line
+	return rule, true
+}
+
+// ignored 判断相对于工作区根目录的路径是否被忽略
+func (r ignoreRules) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		// 规则只作用于 .gitignore 所在目录之下
+		name := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			name = rel[len(rule.base)+1:]
+		}
+
+		var matched bool
+		if rule.anchored {
+			matched = matchGlob(rule.pattern, name)
+		} else {
+			matched, _ = path.Match(rule.pattern, path.Base(name))
+		}
+		if matched {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,153 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		line string
+		want ignoreRule
+		ok   bool
+	}{
+		{"", ignoreRule{}, false},
+		{"   ", ignoreRule{}, false},
+		{"# 注释", ignoreRule{}, false},
+		{"/", ignoreRule{}, false},
+		{"!", ignoreRule{}, false},
+		{"*.log", ignoreRule{pattern: "*.log"}, true},
+		{"*.log  \r", ignoreRule{pattern: "*.log"}, true},
+		{"!keep.log", ignoreRule{pattern: "keep.log", negate: true}, true},
+		{"build/", ignoreRule{pattern: "build", dirOnly: true}, true},
+		{"/build", ignoreRule{pattern: "build", anchored: true}, true},
+		{"/build/", ignoreRule{pattern: "build", dirOnly: true, anchored: true}, true},
+		{"docs/*.tmp", ignoreRule{pattern: "docs/*.tmp", anchored: true}, true},
+		{"**/cache", ignoreRule{pattern: "**/cache", anchored: true}, true},
+		{"!/out/", ignoreRule{pattern: "out", negate: true, dirOnly: true, anchored: true}, true},
+		{`\#file`, ignoreRule{pattern: "#file"}, true},
+		{`\!file`, ignoreRule{pattern: "!file"}, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseIgnoreRule(tt.line, "")
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseIgnoreRule(%q) = %+v, %v，应为 %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// parseRules 解析同一个 .gitignore 中的多行规则
+func parseRules(base string, lines ...string) ignoreRules {
+	var rules ignoreRules
+	for _, line := range lines {
+		if rule, ok := parseIgnoreRule(line, base); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func TestIgnored(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules ignoreRules
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"没有规则", nil, "a.md", false, false},
+
+		// 不含 / 的规则匹配任意一级的文件名
+		{"匹配文件名", parseRules("", "*.log"), "a.log", false, true},
+		{"匹配子目录中的文件名", parseRules("", "*.log"), "x/y/a.log", false, true},
+		{"匹配目录名", parseRules("", "node_modules"), "x/node_modules", true, true},
+		{"不匹配", parseRules("", "*.log"), "a.md", false, false},
+
+		// 以 / 结尾的规则只匹配目录
+		{"目录规则匹配目录", parseRules("", "build/"), "x/build", true, true},
+		{"目录规则不匹配文件", parseRules("", "build/"), "x/build", false, false},
+
+		// 含 / 的规则相对于 .gitignore 所在目录
+		{"锚定规则匹配根目录", parseRules("", "/build"), "build", true, true},
+		{"锚定规则不匹配子目录", parseRules("", "/build"), "x/build", true, false},
+		{"中间含 / 的规则", parseRules("", "docs/*.tmp"), "docs/a.tmp", false, true},
+		{"中间含 / 的规则不匹配其他目录", parseRules("", "docs/*.tmp"), "x/docs/a.tmp", false, false},
+		{"** 匹配任意一级目录", parseRules("", "**/cache"), "x/y/cache", true, true},
+		{"** 匹配零级目录", parseRules("", "**/cache"), "cache", true, true},
+		{"** 匹配目录下的所有文件", parseRules("", "logs/**"), "logs/a/b.txt", false, true},
+		{"中间的 **", parseRules("", "a/**/b"), "a/x/y/b", false, true},
+
+		// ! 重新包含之前被忽略的路径，后面的规则优先
+		{"取反", parseRules("", "*.log", "!keep.log"), "keep.log", false, false},
+		{"取反后的其他文件仍被忽略", parseRules("", "*.log", "!keep.log"), "other.log", false, true},
+		{"取反后再次忽略", parseRules("", "*.log", "!keep.log", "keep.*"), "keep.log", false, true},
+		{"取反目录规则不影响文件", parseRules("", "out*", "!out/"), "out", false, true},
+		{"取反目录规则", parseRules("", "out*", "!out/"), "out", true, false},
+
+		// 子目录中的 .gitignore 只作用于该目录之下
+		{"子目录规则", parseRules("docs", "*.tmp"), "docs/a.tmp", false, true},
+		{"子目录规则不作用于其他目录", parseRules("docs", "*.tmp"), "a.tmp", false, false},
+		{"子目录规则不作用于前缀相同的目录", parseRules("docs", "*.tmp"), "docs2/a.tmp", false, false},
+		{"子目录中的锚定规则", parseRules("docs", "/build"), "docs/build", true, true},
+		{"子目录中的锚定规则不匹配更深的目录", parseRules("docs", "/build"), "docs/x/build", true, false},
+		{"子目录取反根目录的规则", append(parseRules("", "*.log"), parseRules("docs", "!*.log")...), "docs/a.log", false, false},
+		{"子目录取反不影响其他目录", append(parseRules("", "*.log"), parseRules("docs", "!*.log")...), "a.log", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.ignored(tt.rel, tt.isDir); got != tt.want {
+				t.Errorf("ignored(%q, %v) = %v，应为 %v", tt.rel, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreRulesWithFile(t *testing.T) {
+	root := t.TempDir()
+	docs := filepath.Join(root, "docs")
+	if err := os.Mkdir(docs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("# 注释\n*.log\n\nbuild/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(docs, ".gitignore"), []byte("!keep.log\n/tmp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rootRules := ignoreRules(nil).withFile(root, "")
+	if len(rootRules) != 2 {
+		t.Fatalf("根目录的规则数为 %d，应为 2", len(rootRules))
+	}
+	docsRules := rootRules.withFile(docs, "docs")
+	if len(docsRules) != 4 || len(rootRules) != 2 {
+		t.Fatalf("规则数为 %d 和 %d，应为 4 和 2", len(docsRules), len(rootRules))
+	}
+
+	// 没有 .gitignore 的目录返回原列表
+	if rules := docsRules.withFile(filepath.Join(root, "missing"), "missing"); len(rules) != len(docsRules) {
+		t.Errorf("没有 .gitignore 时规则数为 %d，应为 %d", len(rules), len(docsRules))
+	}
+
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"a.log", false, true},
+		{"docs/a.log", false, true},
+		{"docs/keep.log", false, false},
+		{"docs/tmp", true, true},
+		{"tmp", true, false},
+		{"docs/build", true, true},
+		{"docs/a.md", false, false},
+	}
+	for _, tt := range tests {
+		if got := docsRules.ignored(tt.rel, tt.isDir); got != tt.want {
+			t.Errorf("ignored(%q, %v) = %v，应为 %v", tt.rel, tt.isDir, got, tt.want)
+		}
+	}
+}
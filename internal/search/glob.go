@@ -0,0 +1,79 @@
+package search
+
+import (
+	"path"
+	"strings"
+)
+
+// ParseGlobs 解析以逗号分隔的多个 glob 模式（如 "docs/**, *.md"），忽略空白项
+func ParseGlobs(s string) []string {
+	var globs []string
+	for _, glob := range strings.Split(s, ",") {
+		glob = strings.TrimSpace(glob)
+		glob = strings.TrimPrefix(glob, "./")
+		glob = strings.TrimSuffix(glob, "/")
+		if glob != "" {
+			globs = append(globs, glob)
+		}
+	}
+	return globs
+}
+
+// matchGlob 判断以 / 分隔的相对路径是否与模式完全匹配
+// 单个 * 不跨越目录，** 作为一段时匹配零个或多个目录
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments 逐段匹配模式和路径
+func matchSegments(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			// 连续的 ** 等同于一个
+			for len(patterns) > 0 && patterns[0] == "**" {
+				patterns = patterns[1:]
+			}
+			if len(patterns) == 0 {
+				return true
+			}
+			for i := range names {
+				if matchSegments(patterns, names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(names) == 0 {
+			return false
+		}
+		if ok, err := path.Match(patterns[0], names[0]); err != nil || !ok {
+			return false
+		}
+		patterns, names = patterns[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// matchAnyGlob 判断相对路径是否与任意一个模式匹配
+// 不含 / 的模式（如 *.md、drafts）与路径中的任意一段匹配即可；
+// 含 / 的模式与整个路径或它所在的某个上级目录匹配即可（如 docs/guide 包含该目录下的所有文件）
+func matchAnyGlob(globs []string, rel string) bool {
+	segments := strings.Split(rel, "/")
+	for _, glob := range globs {
+		if !strings.Contains(glob, "/") {
+			for _, segment := range segments {
+				if ok, err := path.Match(glob, segment); err == nil && ok {
+					return true
+				}
+			}
+			continue
+		}
+		for i := len(segments); i > 0; i-- {
+			if matchGlob(glob, strings.Join(segments[:i], "/")) {
+				return true
+			}
+		}
+	}
+	return false
+}
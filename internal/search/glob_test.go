@@ -0,0 +1,98 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGlobs(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"*.md", []string{"*.md"}},
+		{"docs/**, *.md", []string{"docs/**", "*.md"}},
+		{"./docs/, drafts/", []string{"docs", "drafts"}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseGlobs(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseGlobs(%q) = %q，应为 %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.md", "a.md", true},
+		{"*.md", "docs/a.md", false}, // * 不跨越目录
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "docs/guide/a.md", false},
+		{"docs/?.md", "docs/a.md", true},
+		{"docs/[ab].md", "docs/c.md", false},
+
+		// ** 匹配零个或多个目录
+		{"**/*.md", "a.md", true},
+		{"**/*.md", "docs/guide/a.md", true},
+		{"docs/**/*.md", "docs/a.md", true},
+		{"docs/**/*.md", "docs/x/y/a.md", true},
+		{"docs/**/*.md", "other/a.md", false},
+		{"docs/**", "docs/a/b", true},
+		{"docs/**", "docs", true},
+		{"**/**/a.md", "x/a.md", true},
+		{"**/drafts/*", "x/y/drafts/a.md", true},
+		{"**/drafts/*", "x/y/drafts", false},
+
+		// 只有作为一段时 ** 才匹配多级目录
+		{"docs**", "docs/a", false},
+		{"a/**b", "a/x/b", false},
+
+		// 无效的模式不匹配任何路径
+		{"[", "[", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v，应为 %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchAnyGlob(t *testing.T) {
+	tests := []struct {
+		globs []string
+		rel   string
+		want  bool
+	}{
+		{nil, "a.md", false},
+
+		// 不含 / 的模式与任意一段匹配
+		{[]string{"*.md"}, "docs/guide/a.md", true},
+		{[]string{"drafts"}, "docs/drafts/a.md", true},
+		{[]string{"drafts"}, "docs/drafts.md", false},
+
+		// 含 / 的模式与整个路径或上级目录匹配
+		{[]string{"docs/guide"}, "docs/guide/a.md", true},
+		{[]string{"docs/guide"}, "docs/guide2/a.md", false},
+		{[]string{"docs/guide"}, "other/docs/guide/a.md", false},
+		{[]string{"docs/*/a.md"}, "docs/x/a.md", true},
+		{[]string{"docs/**"}, "docs/x/y/a.md", true},
+		{[]string{"**/guide"}, "x/y/guide/a.md", true},
+
+		// 任意一个模式匹配即可
+		{[]string{"*.txt", "docs/**"}, "docs/a.md", true},
+		{[]string{"*.txt", "notes/**"}, "docs/a.md", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchAnyGlob(tt.globs, tt.rel); got != tt.want {
+			t.Errorf("matchAnyGlob(%q, %q) = %v，应为 %v", tt.globs, tt.rel, got, tt.want)
+		}
+	}
+}
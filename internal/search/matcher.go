@@ -0,0 +1,111 @@
+package search
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrEmptyQuery 查找内容为空
+var ErrEmptyQuery = errors.New("查找内容为空")
+
+// Options 查找选项
+type Options struct {
+	Regex     bool // 把查找内容当作正则表达式
+	MatchCase bool // 区分大小写
+	WholeWord bool // 全词匹配：匹配内容前后不能紧接字母、数字或下划线（汉字和假名除外）
+}
+
+// Match 一处匹配，Start 和 End 为文本中的字节偏移
+type Match struct {
+	Start  int
+	End    int
+	groups []int // 正则表达式分组的位置，用于展开替换内容中的 $1 等引用
+}
+
+// Matcher 编译后的查找条件
+type Matcher struct {
+	re   *regexp.Regexp
+	opts Options
+}
+
+// Compile 编译查找条件，正则表达式中 ^ 和 $ 匹配每一行的开头和结尾
+func Compile(query string, opts Options) (*Matcher, error) {
+	if query == "" {
+		return nil, ErrEmptyQuery
+	}
+
+	pattern := query
+	if !opts.Regex {
+		pattern = regexp.QuoteMeta(query)
+	}
+	flags := "(?m)"
+	if !opts.MatchCase {
+		flags = "(?mi)"
+	}
+
+	re, err := regexp.Compile(flags + pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{re: re, opts: opts}, nil
+}
+
+// FindAll 查找文本中的所有匹配，忽略长度为零的匹配
+func (m *Matcher) FindAll(text string) []Match {
+	var matches []Match
+	for _, loc := range m.re.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if start == end {
+			continue
+		}
+		if m.opts.WholeWord && !isWholeWord(text, start, end) {
+			continue
+		}
+		matches = append(matches, Match{Start: start, End: end, groups: loc})
+	}
+	return matches
+}
+
+// Expand 获取一处匹配的替换结果，正则模式下展开 $1、${name} 等分组引用
+func (m *Matcher) Expand(text string, match Match, replacement string) string {
+	if !m.opts.Regex || match.groups == nil {
+		return replacement
+	}
+	return string(m.re.ExpandString(nil, replacement, text, match.groups))
+}
+
+// ReplaceAll 替换文本中的指定匹配（必须按位置排列且互不重叠），返回替换后的文本
+func (m *Matcher) ReplaceAll(text string, matches []Match, replacement string) string {
+	var builder strings.Builder
+	last := 0
+	for _, match := range matches {
+		builder.WriteString(text[last:match.Start])
+		builder.WriteString(m.Expand(text, match, replacement))
+		last = match.End
+	}
+	builder.WriteString(text[last:])
+	return builder.String()
+}
+
+// isWholeWord 判断匹配内容前后是否都不是单词字符
+func isWholeWord(text string, start, end int) bool {
+	if before, _ := utf8.DecodeLastRuneInString(text[:start]); start > 0 && isWordRune(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(text[end:]); end < len(text) && isWordRune(after) {
+		return false
+	}
+	return true
+}
+
+// isWordRune 判断字符是否为单词字符（字母、数字或下划线）
+// 中文和日文不用空格分词，汉字、平假名和片假名各自成词，不与相邻的字符连成一个单词
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+		return false
+	}
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
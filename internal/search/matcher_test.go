@@ -0,0 +1,135 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// matchTexts 获取所有匹配的文本
+func matchTexts(text string, matches []Match) []string {
+	var texts []string
+	for _, match := range matches {
+		texts = append(texts, text[match.Start:match.End])
+	}
+	return texts
+}
+
+func TestMatcherFindAll(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  Options
+		text  string
+		want  []string
+	}{
+		{"普通文本", "go", Options{}, "Go go GO", []string{"Go", "go", "GO"}},
+		{"区分大小写", "go", Options{MatchCase: true}, "Go go GO", []string{"go"}},
+		{"特殊字符按原样查找", "a.b*", Options{}, "a.b* axb", []string{"a.b*"}},
+		{"中文", "中文", Options{}, "中文和中文", []string{"中文", "中文"}},
+		{"不区分大小写的非 ASCII 字母", "straße", Options{}, "STRASSE Straße", []string{"Straße"}},
+
+		// 全词匹配
+		{"全词", "go", Options{WholeWord: true}, "go golang ago go_ go.", []string{"go", "go"}},
+		{"全词与数字相邻", "v1", Options{WholeWord: true}, "v1 v10 v1.2", []string{"v1", "v1"}},
+		{"全词与非 ASCII 字母相邻", "cafe", Options{WholeWord: true}, "cafe caféx éclair cafe", []string{"cafe", "cafe"}},
+		{"全词与汉字相邻", "Go", Options{WholeWord: true}, "学习Go语言，Golang", []string{"Go"}},
+		{"全词查找汉字", "中文", Options{WholeWord: true}, "这是中文文本", []string{"中文"}},
+		{"全词与假名相邻", "API", Options{WholeWord: true}, "APIを使うカタカナAPIです APIs", []string{"API", "API"}},
+		{"全词查找假名", "ひらがな", Options{WholeWord: true}, "これはひらがなです", []string{"ひらがな"}},
+		{"全词与韩文相邻", "API", Options{WholeWord: true}, "API는 APIs", []string{}},
+
+		// 正则表达式
+		{"正则", `\d+`, Options{Regex: true}, "a1 b22 c333", []string{"1", "22", "333"}},
+		{"正则中的 ^ 匹配每一行", `^#+`, Options{Regex: true}, "# 一\n正文 #\n## 二", []string{"#", "##"}},
+		{"正则中的 $ 匹配每一行", `\s+$`, Options{Regex: true}, "a  \nb\t\nc", []string{"  ", "\t"}},
+		{"忽略空匹配", `x*`, Options{Regex: true}, "axxb", []string{"xx"}},
+		{"正则全词", `go\w*`, Options{Regex: true, WholeWord: true}, "go golang ago", []string{"go", "golang"}},
+		{"正则区分大小写", `[a-z]+`, Options{Regex: true, MatchCase: true}, "abc DEF", []string{"abc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("Compile(%q) 出错：%v", tt.query, err)
+			}
+			got := matchTexts(tt.text, m.FindAll(tt.text))
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll(%q) = %q，应为 %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	if _, err := Compile("", Options{}); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("查找内容为空时的错误为 %v，应为 ErrEmptyQuery", err)
+	}
+	if _, err := Compile("(", Options{Regex: true}); err == nil {
+		t.Error("无效的正则表达式应出错")
+	}
+	if _, err := Compile("(", Options{}); err != nil {
+		t.Errorf("非正则模式下 ( 应按原样查找，出错：%v", err)
+	}
+}
+
+func TestMatcherExpand(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		opts        Options
+		text        string
+		replacement string
+		want        string
+	}{
+		{"普通替换", "a", Options{}, "a b a", "x", "x b x"},
+		{"非正则模式不展开 $1", "a", Options{}, "a", "$1", "$1"},
+		{"分组编号", `(\w+)@(\w+)`, Options{Regex: true}, "user@example", "$2 的 $1", "example 的 user"},
+		{"分组编号后接字母", `(\w+)`, Options{Regex: true}, "a", "${1}x", "ax"},
+		{"命名分组", `(?P<key>\w+)=(?P<value>\w+)`, Options{Regex: true}, "a=1, b=2", "${value}=${key}", "1=a, 2=b"},
+		{"整个匹配", `\d+`, Options{Regex: true}, "第1章", "[$0]", "第[1]章"},
+		{"$$ 表示 $", `\d+`, Options{Regex: true}, "1", "$$$0", "$1"},
+		{"不存在的分组为空", `(a)`, Options{Regex: true}, "a", "[$2]", "[]"},
+		{"替换为空", "，", Options{}, "一，二", "", "一二"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.query, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := m.ReplaceAll(tt.text, m.FindAll(tt.text), tt.replacement)
+			if got != tt.want {
+				t.Errorf("ReplaceAll(%q, %q) = %q，应为 %q", tt.text, tt.replacement, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherReplaceSelected(t *testing.T) {
+	// 只替换选中的匹配
+	m, err := Compile(`(\d)`, Options{Regex: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "1 2 3"
+	matches := m.FindAll(text)
+	if len(matches) != 3 {
+		t.Fatalf("找到 %d 处匹配，应为 3", len(matches))
+	}
+
+	if got := m.ReplaceAll(text, []Match{matches[0], matches[2]}, "<$1>"); got != "<1> 2 <3>" {
+		t.Errorf("ReplaceAll() = %q，应为 %q", got, "<1> 2 <3>")
+	}
+	if got := m.ReplaceAll(text, nil, "x"); got != text {
+		t.Errorf("没有选中匹配时 ReplaceAll() = %q，应保持 %q", got, text)
+	}
+	if got := m.Expand(text, matches[1], "[$1]"); got != "[2]" {
+		t.Errorf("Expand() = %q，应为 %q", got, "[2]")
+	}
+}
@@ -0,0 +1,218 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"markup/internal/workspace"
+)
+
+// MaxMatches 工作区搜索最多返回的匹配数
+const MaxMatches = 5000
+
+// snippetContext 结果摘要中匹配内容之前最多保留的字节数，过长的行会被截断
+const snippetContext = 40
+
+// snippetTail 结果摘要中匹配内容之后最多保留的字节数
+const snippetTail = 120
+
+// ErrTooManyMatches 匹配数超过 MaxMatches，搜索提前结束
+var ErrTooManyMatches = errors.New("匹配过多")
+
+// Request 工作区搜索请求
+type Request struct {
+	Root     string            // 工作区根目录
+	Matcher  *Matcher          // 查找条件
+	Include  []string          // 只搜索与这些 glob 匹配的文件，为空时搜索所有文件
+	Exclude  []string          // 跳过与这些 glob 匹配的文件和目录
+	Contents map[string]string // 已打开且有未保存修改的文件内容，优先于磁盘上的内容
+}
+
+// LineMatch 文件中的一处匹配
+type LineMatch struct {
+	Line    int    // 行号（从1开始）
+	Column  int    // 列号（从1开始，按字符计）
+	Snippet string // 匹配所在行的内容，过长时被截断
+	Start   int    // 匹配内容在 Snippet 中的起点（字节偏移）
+	End     int    // 匹配内容在 Snippet 中的终点（字节偏移）
+	Match   Match  // 匹配在文件内容中的位置
+}
+
+// Text 获取匹配的内容
+func (m *LineMatch) Text() string {
+	return m.Snippet[m.Start:m.End]
+}
+
+// FileResult 一个文件中的所有匹配
+type FileResult struct {
+	Path    string      // 文件的绝对路径
+	RelPath string      // 相对于工作区根目录的路径（以 / 分隔）
+	Content string      // 搜索时的文件内容
+	Matches []LineMatch // 按位置排列的匹配
+}
+
+// SearchWorkspace 在工作区的所有 Markdown 文件中查找，每找到一个有匹配的文件就调用一次 onResult
+// 跳过隐藏文件、被 .gitignore 忽略的文件以及不符合 Include/Exclude 的文件
+// ctx 被取消时返回 ctx.Err()，匹配过多时返回 ErrTooManyMatches
+func SearchWorkspace(ctx context.Context, req *Request, onResult func(*FileResult)) error {
+	s := &workspaceSearch{ctx: ctx, req: req, onResult: onResult}
+	return s.walk(req.Root, "", ignoreRules(nil).withFile(req.Root, ""))
+}
+
+// workspaceSearch 一次工作区搜索的状态
+type workspaceSearch struct {
+	ctx      context.Context
+	req      *Request
+	onResult func(*FileResult)
+	count    int // 已找到的匹配数
+}
+
+// walk 递归搜索目录，rel 为目录相对于工作区根目录的路径
+func (s *workspaceSearch) walk(dir, rel string, rules ignoreRules) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// 无法读取的子目录直接忽略，与文件树的处理一致
+		if rel == "" {
+			return err
+		}
+		return nil
+	}
+
+	for _, entry := range entries {
+		if err := s.ctx.Err(); err != nil {
+			return err
+		}
+
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		childRel := name
+		if rel != "" {
+			childRel = rel + "/" + name
+		}
+		childPath := filepath.Join(dir, name)
+		isDir := entry.IsDir()
+
+		if rules.ignored(childRel, isDir) || matchAnyGlob(s.req.Exclude, childRel) {
+			continue
+		}
+		if isDir {
+			if err := s.walk(childPath, childRel, rules.withFile(childPath, childRel)); err != nil {
+				return err
+			}
+			continue
+		}
+		if !workspace.IsMarkdownFile(name) {
+			continue
+		}
+		if len(s.req.Include) > 0 && !matchAnyGlob(s.req.Include, childRel) {
+			continue
+		}
+		if err := s.searchFile(childPath, childRel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchFile 搜索单个文件，无法读取的文件直接跳过
+func (s *workspaceSearch) searchFile(path, rel string) error {
+	content, ok := s.req.Contents[path]
+	if !ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		content = string(data)
+	}
+
+	matches := s.req.Matcher.FindAll(content)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	truncated := false
+	if s.count+len(matches) > MaxMatches {
+		matches = matches[:MaxMatches-s.count]
+		truncated = true
+	}
+	s.count += len(matches)
+
+	s.onResult(&FileResult{
+		Path:    path,
+		RelPath: rel,
+		Content: content,
+		Matches: lineMatches(content, matches),
+	})
+	if truncated || s.count >= MaxMatches {
+		return ErrTooManyMatches
+	}
+	return nil
+}
+
+// lineMatches 计算每处匹配所在的行列和摘要
+func lineMatches(content string, matches []Match) []LineMatch {
+	result := make([]LineMatch, 0, len(matches))
+
+	// 按顺序扫描文本，避免每处匹配都从头计算行号
+	line, lineStart, scanned := 1, 0, 0
+	for _, match := range matches {
+		for {
+			next := strings.IndexByte(content[scanned:match.Start], '\n')
+			if next < 0 {
+				break
+			}
+			line++
+			lineStart = scanned + next + 1
+			scanned = lineStart
+		}
+		scanned = match.Start
+
+		lineEnd := len(content)
+		if next := strings.IndexByte(content[match.Start:], '\n'); next >= 0 {
+			lineEnd = match.Start + next
+		}
+		// CRLF 换行的 \r 不显示在摘要中
+		if lineEnd > match.Start && content[lineEnd-1] == '\r' {
+			lineEnd--
+		}
+		// 跨行的匹配只显示第一行
+		end := min(match.End, lineEnd)
+
+		// 截断过长的行，保证匹配内容完整显示
+		from, to := lineStart, lineEnd
+		prefix, suffix := "", ""
+		if match.Start-from > snippetContext {
+			from = runeStart(content, match.Start-snippetContext)
+			prefix = "…"
+		}
+		if to-end > snippetTail {
+			to = runeStart(content, end+snippetTail)
+			suffix = "…"
+		}
+
+		start := len(prefix) + match.Start - from
+		result = append(result, LineMatch{
+			Line:    line,
+			Column:  utf8.RuneCountInString(content[lineStart:match.Start]) + 1,
+			Snippet: prefix + content[from:to] + suffix,
+			Start:   start,
+			End:     start + end - match.Start,
+			Match:   match,
+		})
+	}
+	return result
+}
+
+// runeStart 把字节偏移向前调整到字符的起点
+func runeStart(s string, offset int) int {
+	for offset > 0 && !utf8.RuneStart(s[offset]) {
+		offset--
+	}
+	return offset
+}
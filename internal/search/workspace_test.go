@@ -0,0 +1,293 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTree 在 root 下创建测试文件，键为以 / 分隔的相对路径
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// searchAll 执行工作区搜索，返回所有结果
+func searchAll(t *testing.T, ctx context.Context, req *Request) ([]*FileResult, error) {
+	t.Helper()
+	var results []*FileResult
+	err := SearchWorkspace(ctx, req, func(result *FileResult) {
+		results = append(results, result)
+	})
+	return results, err
+}
+
+// resultPaths 获取结果中的相对路径
+func resultPaths(results []*FileResult) []string {
+	var paths []string
+	for _, result := range results {
+		paths = append(paths, result.RelPath)
+	}
+	return paths
+}
+
+func TestSearchWorkspaceFilters(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":          "build/\n*.draft.md\n",
+		"a.md":                "目标",
+		"a.draft.md":          "目标",
+		"notes.txt":           "目标",
+		".hidden/a.md":        "目标",
+		"build/a.md":          "目标",
+		"docs/a.md":           "目标",
+		"docs/guide/a.md":     "目标",
+		"docs/guide/b.md":     "没有",
+		"docs/.gitignore":     "!keep.draft.md\nprivate/\n",
+		"docs/keep.draft.md":  "目标",
+		"docs/private/a.md":   "目标",
+		"drafts/a.md":         "目标",
+		"drafts/docs/a.md":    "目标",
+		"vendor/docs/a.md":    "目标",
+		"vendor/docs/skip.md": "目标",
+	})
+	matcher, err := Compile("目标", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		include string
+		exclude string
+		want    []string
+	}{
+		{
+			"只应用 .gitignore", "", "",
+			[]string{"a.md", "docs/a.md", "docs/guide/a.md", "docs/keep.draft.md", "drafts/a.md", "drafts/docs/a.md", "vendor/docs/a.md", "vendor/docs/skip.md"},
+		},
+		{"包含目录", "docs/**", "", []string{"docs/a.md", "docs/guide/a.md", "docs/keep.draft.md"}},
+		{"包含任意一级的目录名", "docs", "", []string{"docs/a.md", "docs/guide/a.md", "docs/keep.draft.md", "drafts/docs/a.md", "vendor/docs/a.md", "vendor/docs/skip.md"}},
+		{"排除目录", "", "drafts, vendor", []string{"a.md", "docs/a.md", "docs/guide/a.md", "docs/keep.draft.md"}},
+		{"包含和排除同时使用", "docs", "guide, skip.md", []string{"docs/a.md", "docs/keep.draft.md", "drafts/docs/a.md", "vendor/docs/a.md"}},
+		{"排除优先于包含", "*.md", "*.md", nil},
+		{"被 .gitignore 忽略的文件不能通过包含找回", "build/**", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := searchAll(t, context.Background(), &Request{
+				Root:    root,
+				Matcher: matcher,
+				Include: ParseGlobs(tt.include),
+				Exclude: ParseGlobs(tt.exclude),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resultPaths(results); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("搜索到 %q，应为 %q", got, tt.want)
+			}
+			for _, result := range results {
+				if result.Path != filepath.Join(root, filepath.FromSlash(result.RelPath)) {
+					t.Errorf("%s 的绝对路径为 %s", result.RelPath, result.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchWorkspaceContents(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.md": "磁盘上的目标",
+		"b.md": "磁盘上的目标",
+		"c.md": "没有匹配",
+	})
+	matcher, err := Compile("目标", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 未保存的内容优先于磁盘上的内容
+	results, err := searchAll(t, context.Background(), &Request{
+		Root:    root,
+		Matcher: matcher,
+		Contents: map[string]string{
+			filepath.Join(root, "a.md"):       "编辑器中的目标\n第二个目标",
+			filepath.Join(root, "b.md"):       "已经删除了",
+			filepath.Join(root, "c.md"):       "新加的目标",
+			filepath.Join(root, "missing.md"): "不在工作区中的目标",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := resultPaths(results), []string{"a.md", "c.md"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("搜索到 %q，应为 %q", got, want)
+	}
+	if results[0].Content != "编辑器中的目标\n第二个目标" {
+		t.Errorf("结果中的内容为 %q，应为编辑器中的内容", results[0].Content)
+	}
+	if len(results[0].Matches) != 2 || results[0].Matches[1].Line != 2 {
+		t.Errorf("a.md 的匹配为 %+v，应在第 1 行和第 2 行", results[0].Matches)
+	}
+}
+
+func TestSearchWorkspaceCancel(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.md":     "目标",
+		"b.md":     "目标",
+		"sub/c.md": "目标",
+	})
+	matcher, err := Compile("目标", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("开始前取消", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := searchAll(t, ctx, &Request{Root: root, Matcher: matcher})
+		if !errors.Is(err, context.Canceled) || len(results) != 0 {
+			t.Errorf("返回 %d 个结果和错误 %v，应没有结果并返回 context.Canceled", len(results), err)
+		}
+	})
+
+	t.Run("搜索过程中取消", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		count := 0
+		err := SearchWorkspace(ctx, &Request{Root: root, Matcher: matcher}, func(*FileResult) {
+			count++
+			cancel()
+		})
+		if !errors.Is(err, context.Canceled) || count != 1 {
+			t.Errorf("取消后收到 %d 个结果，错误为 %v，应只收到 1 个并返回 context.Canceled", count, err)
+		}
+	})
+
+	t.Run("根目录不存在", func(t *testing.T) {
+		if _, err := searchAll(t, context.Background(), &Request{Root: filepath.Join(root, "missing"), Matcher: matcher}); err == nil {
+			t.Error("根目录不存在时应出错")
+		}
+	})
+}
+
+func TestSearchWorkspaceTooManyMatches(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"a.md": strings.Repeat("x\n", MaxMatches-10),
+		"b.md": strings.Repeat("x\n", 20),
+		"c.md": "x",
+	})
+	matcher, err := Compile("x", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := searchAll(t, context.Background(), &Request{Root: root, Matcher: matcher})
+	if !errors.Is(err, ErrTooManyMatches) {
+		t.Errorf("错误为 %v，应为 ErrTooManyMatches", err)
+	}
+	total := 0
+	for _, result := range results {
+		total += len(result.Matches)
+	}
+	if len(results) != 2 || total != MaxMatches {
+		t.Errorf("返回 %d 个文件共 %d 处匹配，应为 2 个文件共 %d 处", len(results), total, MaxMatches)
+	}
+}
+
+func TestLineMatches(t *testing.T) {
+	longPrefix := strings.Repeat("前", 30) // 90 字节
+	longSuffix := strings.Repeat("后", 60) // 180 字节
+
+	tests := []struct {
+		name    string
+		query   string
+		opts    Options
+		content string
+		want    []LineMatch // 只比较 Line、Column、Snippet 和匹配内容
+	}{
+		{
+			"多行",
+			"目标", Options{},
+			"第一行\n有目标的行\n\n目标 和 目标",
+			[]LineMatch{
+				{Line: 2, Column: 2, Snippet: "有目标的行"},
+				{Line: 4, Column: 1, Snippet: "目标 和 目标"},
+				{Line: 4, Column: 6, Snippet: "目标 和 目标"},
+			},
+		},
+		{
+			"列号按字符计算",
+			"x", Options{},
+			"中文é x",
+			[]LineMatch{{Line: 1, Column: 5, Snippet: "中文é x"}},
+		},
+		{
+			"CRLF 换行",
+			"b", Options{},
+			"a\r\nb\r\nab\r\n",
+			[]LineMatch{
+				{Line: 2, Column: 1, Snippet: "b"},
+				{Line: 3, Column: 2, Snippet: "ab"},
+			},
+		},
+		{
+			"跨行的匹配只显示第一行",
+			`a\nb`, Options{Regex: true},
+			"xa\nbx",
+			[]LineMatch{{Line: 1, Column: 2, Snippet: "xa"}},
+		},
+		{
+			"截断过长的行",
+			"目标", Options{},
+			longPrefix + "目标" + longSuffix,
+			[]LineMatch{{Line: 1, Column: 31, Snippet: "…" + strings.Repeat("前", 14) + "目标" + strings.Repeat("后", 40) + "…"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.query, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matches := m.FindAll(tt.content)
+			got := lineMatches(tt.content, matches)
+			if len(got) != len(tt.want) {
+				t.Fatalf("返回 %d 处匹配，应为 %d", len(got), len(tt.want))
+			}
+			for i, match := range got {
+				want := tt.want[i]
+				if match.Line != want.Line || match.Column != want.Column || match.Snippet != want.Snippet {
+					t.Errorf("第 %d 处匹配为 %d:%d %q，应为 %d:%d %q", i+1, match.Line, match.Column, match.Snippet, want.Line, want.Column, want.Snippet)
+				}
+				// 摘要中标出的内容是匹配的第一行
+				text := tt.content[matches[i].Start:matches[i].End]
+				if first, _, _ := strings.Cut(text, "\n"); match.Text() != first {
+					t.Errorf("第 %d 处匹配的内容为 %q，应为 %q", i+1, match.Text(), first)
+				}
+				if match.Match.Start != matches[i].Start || match.Match.End != matches[i].End {
+					t.Errorf("第 %d 处匹配的位置为 %d-%d，应为 %d-%d", i+1, match.Match.Start, match.Match.End, matches[i].Start, matches[i].End)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"math"
+
+	"fyne.io/fyne/v2"
+)
+
+// 编辑器缩放范围
+const (
+	minZoom  = 0.5
+	maxZoom  = 3.0
+	zoomStep = 0.1
+)
+
+// applyAppearance 按当前的配色和缩放比例更新应用主题
+func (sc *GuiController) applyAppearance() {
+	fyne.CurrentApp().Settings().SetTheme(NewGitHubTheme().WithDark(sc.darkTheme).WithScale(sc.zoom))
+	sc.refreshMainMenu()
+	// 文字大小变化后重新计算高亮的位置
+	sc.renderFindHighlights()
+}
+
+// setDarkTheme 切换深色或浅色配色
+func (sc *GuiController) setDarkTheme(dark bool) {
+	if sc.darkTheme == dark {
+		return
+	}
+	sc.darkTheme = dark
+	sc.applyAppearance()
+}
+
+// setZoom 设置文字缩放比例，限制在允许的范围内
+func (sc *GuiController) setZoom(zoom float32) {
+	// 按步长取整，避免多次放大缩小后出现 0.9999 之类的比例
+	zoom = float32(math.Round(float64(zoom)/zoomStep) * zoomStep)
+	zoom = min(max(zoom, minZoom), maxZoom)
+	if sc.zoom == zoom {
+		return
+	}
+	sc.zoom = zoom
+	sc.applyAppearance()
+}
+
+// zoomIn 放大文字
+func (sc *GuiController) zoomIn() {
+	sc.setZoom(sc.zoom + zoomStep)
+}
+
+// zoomOut 缩小文字
+func (sc *GuiController) zoomOut() {
+	sc.setZoom(sc.zoom - zoomStep)
+}
+
+// resetZoom 恢复默认文字大小
+func (sc *GuiController) resetZoom() {
+	sc.setZoom(1)
+}
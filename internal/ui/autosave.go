@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/core"
+)
+
+// startAutosave 按设置的间隔定时保存草稿，只启动一次
+func (sc *GuiController) startAutosave() {
+	if sc.autosaveStarted || sc.drafts == nil || sc.settings.AutosaveInterval <= 0 {
+		return
+	}
+	sc.autosaveStarted = true
+
+	interval := time.Duration(sc.settings.AutosaveInterval) * time.Second
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(sc.autosaveDraft)
+		}
+	}()
+}
+
+// autosaveDraft 把所有有未保存变更的文档写入草稿
+func (sc *GuiController) autosaveDraft() {
+	if !sc.isEditing || sc.drafts == nil {
+		return
+	}
+	for _, doc := range sc.documents {
+		sc.autosaveDocument(doc)
+	}
+}
+
+// autosaveDocument 把有未保存变更的文档写入草稿，没有变更时删除草稿
+func (sc *GuiController) autosaveDocument(doc *document) {
+	if !doc.state.HasUnsavedChanges() {
+		sc.discardDraft(doc)
+		return
+	}
+
+	content := doc.state.GetCurrentContent()
+	if content == doc.lastDraftContent {
+		return
+	}
+
+	filePath := doc.state.GetCurrentFile()
+	if doc.draftID == "" {
+		doc.draftID = sc.draftIDFor(filePath)
+	}
+	draft := &core.Draft{
+		ID:       doc.draftID,
+		FilePath: filePath,
+		Content:  content,
+		SavedAt:  time.Now(),
+	}
+	if err := sc.drafts.Save(draft); err != nil {
+		// 只提示一次，之后继续静默重试
+		if !sc.autosaveFailed {
+			sc.autosaveFailed = true
+			dialog.ShowError(err, sc.window)
+		}
+		return
+	}
+	doc.lastDraftContent = content
+}
+
+// discardDraft 删除文档的草稿（文档已保存、用户放弃更改或关闭文档时调用）
+func (sc *GuiController) discardDraft(doc *document) {
+	if sc.drafts == nil || doc == nil || doc.draftID == "" {
+		return
+	}
+	sc.drafts.Remove(doc.draftID)
+	doc.lastDraftContent = ""
+}
+
+// draftIDFor 获取文档第一次写入草稿时使用的ID：文件已有草稿（例如上次选择稍后恢复）时
+// 沿用该草稿，避免同一文件每次运行都留下一份新草稿
+func (sc *GuiController) draftIDFor(filePath string) string {
+	if draft, err := sc.drafts.FindByFile(filePath); err == nil && draft != nil {
+		return draft.ID
+	}
+	return core.NewDraftID()
+}
+
+// offerDraftRecovery 启动时如果存在上次未保存的草稿，询问是否恢复
+func (sc *GuiController) offerDraftRecovery() {
+	if sc.drafts == nil {
+		return
+	}
+	drafts, err := sc.drafts.List()
+	if err != nil || len(drafts) == 0 {
+		return
+	}
+
+	var recoveryDialog *dialog.CustomDialog
+
+	list := widget.NewList(
+		func() int {
+			return len(drafts)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			draft := drafts[id]
+			name := "未命名"
+			if draft.FilePath != "" {
+				name = filepath.Base(draft.FilePath)
+			}
+			obj.(*widget.Label).SetText(name + "    " + draft.SavedAt.Format("2006-01-02 15:04:05"))
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		recoveryDialog.Hide()
+		sc.restoreDraft(drafts[id])
+	}
+
+	discardBtn := widget.NewButton("全部丢弃", func() {
+		recoveryDialog.Hide()
+		for _, draft := range drafts {
+			sc.drafts.Remove(draft.ID)
+		}
+	})
+	laterBtn := widget.NewButton("稍后", func() {
+		recoveryDialog.Hide()
+	})
+
+	message := widget.NewLabel("上次运行时以下文档没有保存，选择一份进行恢复：")
+	content := container.NewBorder(message, nil, nil, nil, list)
+
+	recoveryDialog = dialog.NewCustomWithoutButtons("恢复草稿", content, sc.window)
+	recoveryDialog.SetButtons([]fyne.CanvasObject{discardBtn, laterBtn})
+	recoveryDialog.Resize(fyne.NewSize(480, 320))
+	recoveryDialog.Show()
+}
+
+// restoreDraft 恢复草稿：打开对应文件（如仍存在）并用草稿内容替换编辑器内容，
+// 文件已在标签页中打开时替换该标签页的内容
+func (sc *GuiController) restoreDraft(draft *core.Draft) {
+	// 原始内容取磁盘上的文件，恢复后的内容会显示为未保存
+	original := ""
+	if draft.FilePath != "" {
+		if content, err := sc.appState.LoadFile(draft.FilePath); err == nil {
+			original = content
+		}
+	}
+
+	// 继续使用原草稿，之后的自动保存会覆盖它
+	useDraft := func(doc *document) {
+		doc.draftID = draft.ID
+		doc.lastDraftContent = draft.Content
+	}
+
+	doc := sc.findDocument(draft.FilePath)
+	if doc == nil {
+		doc = sc.newDocument(draft.FilePath, draft.Content, original)
+		sc.openDocument(doc)
+		useDraft(doc)
+		return
+	}
+
+	sc.selectDocument(doc)
+	sc.confirmUnsavedChanges(func() {
+		sc.discardDraft(doc)
+		doc.state.SetOriginalContent(original)
+		doc.state.UpdateDiskSnapshot()
+		doc.editor.setContent(draft.Content)
+		sc.updateWindowTitle()
+		useDraft(doc)
+	})
+}
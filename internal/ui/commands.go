@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"fyne.io/fyne/v2"
+)
+
+// commandScope 命令可以执行的条件
+type commandScope int
+
+const (
+	scopeAlways   commandScope = iota // 任何时候都可以执行
+	scopeEditing                      // 需要编辑界面（如只打开了文件夹）
+	scopeDocument                     // 需要打开的文档
+)
+
+// command 命令：菜单、快捷键和命令面板执行的操作都注册为命令
+type command struct {
+	id       string        // 命令ID，配置文件中用它修改快捷键，如 file.save
+	title    string        // 显示名称
+	shortcut fyne.Shortcut // 当前绑定的快捷键，未绑定时为 nil
+	scope    commandScope  // 可以执行的条件
+	run      func()
+}
+
+// commandRegistry 命令注册表，负责命令与快捷键的对应关系
+type commandRegistry struct {
+	commands   []*command          // 按注册顺序排列
+	byID       map[string]*command // 命令ID -> 命令
+	byShortcut map[string]*command // 快捷键名称 -> 命令
+}
+
+// newCommandRegistry 创建空的命令注册表
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{
+		byID:       make(map[string]*command),
+		byShortcut: make(map[string]*command),
+	}
+}
+
+// register 注册命令及其默认快捷键（可以为 nil）
+func (r *commandRegistry) register(cmd *command) {
+	r.commands = append(r.commands, cmd)
+	r.byID[cmd.id] = cmd
+	if cmd.shortcut != nil {
+		r.byShortcut[cmd.shortcut.ShortcutName()] = cmd
+	}
+}
+
+// lookup 按ID查找命令
+func (r *commandRegistry) lookup(id string) *command {
+	return r.byID[id]
+}
+
+// forShortcut 查找绑定到指定快捷键的命令
+func (r *commandRegistry) forShortcut(shortcut fyne.Shortcut) *command {
+	return r.byShortcut[shortcut.ShortcutName()]
+}
+
+// applyBindings 应用用户配置的快捷键（命令ID -> 快捷键，空字符串表示取消绑定）并检查冲突，
+// 返回发现的问题。多个命令绑定到同一快捷键时，用户配置的优先，其次是先注册的命令
+func (r *commandRegistry) applyBindings(bindings map[string]string) []string {
+	var problems []string
+
+	ids := make([]string, 0, len(bindings))
+	for id := range bindings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	configured := make(map[*command]bool)
+	for _, id := range ids {
+		cmd := r.byID[id]
+		if cmd == nil {
+			problems = append(problems, fmt.Sprintf("未知的命令 %q", id))
+			continue
+		}
+		if bindings[id] == "" {
+			cmd.shortcut = nil
+			configured[cmd] = true
+			continue
+		}
+		shortcut, err := parseShortcut(bindings[id])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("命令 %s 的快捷键 %q 无效：%v", id, bindings[id], err))
+			continue
+		}
+		cmd.shortcut = shortcut
+		configured[cmd] = true
+	}
+
+	// 用户配置的命令先占用快捷键
+	ordered := make([]*command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		if configured[cmd] {
+			ordered = append(ordered, cmd)
+		}
+	}
+	for _, cmd := range r.commands {
+		if !configured[cmd] {
+			ordered = append(ordered, cmd)
+		}
+	}
+
+	r.byShortcut = make(map[string]*command)
+	for _, cmd := range ordered {
+		if cmd.shortcut == nil {
+			continue
+		}
+		name := cmd.shortcut.ShortcutName()
+		if owner := r.byShortcut[name]; owner != nil {
+			problems = append(problems, fmt.Sprintf("快捷键 %s 同时绑定到“%s”和“%s”，已忽略“%s”的绑定",
+				formatShortcut(cmd.shortcut), owner.title, cmd.title, cmd.title))
+			cmd.shortcut = nil
+			continue
+		}
+		r.byShortcut[name] = cmd
+	}
+
+	return problems
+}
+
+// registerCommands 注册所有命令及其默认快捷键
+func (sc *GuiController) registerCommands() {
+	r := newCommandRegistry()
+	add := func(id, title string, shortcut fyne.Shortcut, scope commandScope, run func()) {
+		r.register(&command{id: id, title: title, shortcut: shortcut, scope: scope, run: run})
+	}
+
+	// 文件
+	add("file.new", "新建文件", shortcutNewFile, scopeAlways, sc.createNewFile)
+	add("file.open", "打开文件...", shortcutOpenFile, scopeAlways, sc.openFile)
+	add("file.quickOpen", "快速打开...", shortcutQuickOpen, scopeAlways, sc.showQuickOpen)
+	add("file.openFolder", "打开文件夹...", shortcutOpenFolder, scopeAlways, sc.openFolder)
+	add("file.save", "保存", shortcutSave, scopeDocument, sc.saveFile)
+	add("file.saveAs", "另存为...", shortcutSaveAs, scopeDocument, sc.saveFileAs)
+	add("file.export", "导出 HTML...", shortcutExport, scopeDocument, sc.showExportDialog)
+	add("file.close", "关闭标签页", shortcutClose, scopeDocument, sc.closeCurrentDocument)
+	add("file.clearRecent", "清除最近记录", nil, scopeAlways, sc.clearRecent)
+	add("file.quit", "退出", shortcutQuit, scopeAlways, func() {
+		// 退出前同样要检查未保存的更改，不能使用 Fyne 默认的退出操作
+		sc.OnWindowClose(fyne.CurrentApp().Quit)
+	})
+
+	// 编辑
+	add("edit.undo", "撤销", &fyne.ShortcutUndo{}, scopeDocument, sc.undo)
+	add("edit.redo", "重做", &fyne.ShortcutRedo{}, scopeDocument, sc.redo)
+	add("format.bold", "粗体", shortcutBold, scopeDocument, func() {
+		sc.editorEntry.wrapSelection("**")
+	})
+	add("format.italic", "斜体", shortcutItalic, scopeDocument, func() {
+		sc.editorEntry.wrapSelection("*")
+	})
+	add("format.insertTable", "插入表格", nil, scopeDocument, func() {
+		sc.editorEntry.insertTable()
+	})
+	add("edit.find", "查找...", shortcutFind, scopeDocument, func() {
+		sc.showFind(false)
+	})
+	add("edit.replace", "替换...", shortcutReplace, scopeDocument, func() {
+		sc.showFind(true)
+	})
+	add("edit.findNext", "查找下一个", shortcutFindNext, scopeDocument, sc.findNext)
+	add("edit.findPrevious", "查找上一个", shortcutFindPrev, scopeDocument, sc.findPrevious)
+	add("edit.findInFiles", "在工作区中查找...", shortcutFindInFiles, scopeEditing, sc.showWorkspaceSearch)
+	add("edit.replaceInFiles", "在工作区中替换...", shortcutReplaceInFiles, scopeEditing, sc.showWorkspaceReplace)
+	add("edit.revertReplace", "撤销工作区替换", nil, scopeAlways, sc.revertWorkspaceReplace)
+
+	// 视图
+	add("view.split", "分屏", shortcutSplitMode, scopeEditing, func() {
+		sc.setViewMode(viewModeSplit)
+	})
+	add("view.edit", "仅编辑", shortcutEditMode, scopeEditing, func() {
+		sc.toggleViewMode(viewModeEdit)
+	})
+	add("view.preview", "仅预览", shortcutPreviewMode, scopeEditing, func() {
+		sc.toggleViewMode(viewModePreview)
+	})
+	add("view.refreshFiles", "刷新文件树", nil, scopeEditing, sc.refreshWorkspace)
+	add("view.problems", "问题面板", nil, scopeEditing, sc.toggleProblemsPanel)
+	add("view.runLint", "运行语法检查", nil, scopeDocument, sc.runLintNow)
+	add("view.commandPalette", "命令面板...", shortcutCommandPalette, scopeAlways, sc.showCommandPalette)
+	add("view.lightTheme", "浅色主题", nil, scopeAlways, func() {
+		sc.setDarkTheme(false)
+	})
+	add("view.darkTheme", "深色主题", nil, scopeAlways, func() {
+		sc.setDarkTheme(true)
+	})
+	add("view.zoomIn", "放大", shortcutZoomIn, scopeAlways, sc.zoomIn)
+	add("view.zoomOut", "缩小", shortcutZoomOut, scopeAlways, sc.zoomOut)
+	add("view.zoomReset", "实际大小", shortcutZoomReset, scopeAlways, sc.resetZoom)
+	add("view.nextTab", "下一个标签页", shortcutNextTab, scopeDocument, func() {
+		sc.cycleDocument(1)
+	})
+	add("view.previousTab", "上一个标签页", shortcutPreviousTab, scopeDocument, func() {
+		sc.cycleDocument(-1)
+	})
+
+	// 帮助
+	add("help.cheatsheet", "Markdown 速查表", nil, scopeAlways, sc.showCheatsheet)
+	add("help.about", "关于 MarkUp", nil, scopeAlways, sc.showAbout)
+
+	sc.commands = r
+	sc.bindingProblems = r.applyBindings(sc.settings.Keybindings)
+}
+
+// hasDocument 是否有打开的文档。只打开了文件夹时处于编辑界面，但没有文档和编辑器
+func (sc *GuiController) hasDocument() bool {
+	return sc.current != nil
+}
+
+// commandEnabled 命令在当前状态下是否可以执行
+func (sc *GuiController) commandEnabled(cmd *command) bool {
+	switch cmd.scope {
+	case scopeEditing:
+		return sc.isEditing
+	case scopeDocument:
+		return sc.hasDocument()
+	}
+	return true
+}
+
+// executeCommand 执行命令，当前状态下不可用的命令被忽略
+func (sc *GuiController) executeCommand(cmd *command) {
+	if !sc.commandEnabled(cmd) {
+		return
+	}
+	cmd.run()
+}
+
+// runCommand 按ID执行命令，供工具栏按钮等使用
+func (sc *GuiController) runCommand(id string) {
+	sc.executeCommand(sc.commands.lookup(id))
+}
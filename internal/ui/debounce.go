@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+// debouncer 防抖器，连续触发时只在最后一次触发后延迟执行回调
+type debouncer struct {
+	mutex sync.Mutex    // 互斥锁
+	delay time.Duration // 延迟时间
+	timer *time.Timer   // 当前计时器
+}
+
+// newDebouncer 创建新的防抖器
+func newDebouncer(delay time.Duration) *debouncer {
+	return &debouncer{delay: delay}
+}
+
+// trigger 触发一次，回调会在 UI 线程中执行
+func (d *debouncer) trigger(fn func()) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	d.timer = time.AfterFunc(d.delay, func() {
+		fyne.Do(fn)
+	})
+}
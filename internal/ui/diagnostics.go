@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/lint"
+)
+
+// buildProblemsPanel 构建编辑器下方的问题面板
+func (sc *GuiController) buildProblemsPanel() fyne.CanvasObject {
+	sc.problemsList = widget.NewList(
+		// 条目数量
+		func() int {
+			return len(sc.diagnostics)
+		},
+		// 创建条目
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, widget.NewIcon(nil), nil, label)
+		},
+		// 更新条目
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(sc.diagnostics) {
+				return
+			}
+			d := sc.diagnostics[id]
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(
+				fmt.Sprintf("第%d行，第%d列  %s  [%s]", d.Line, d.Column, d.Message, d.RuleID))
+			row.Objects[1].(*widget.Icon).SetResource(severityIcon(d.Severity))
+		},
+	)
+
+	// 点击问题时跳转到对应位置
+	sc.problemsList.OnSelected = func(id widget.ListItemID) {
+		if id < len(sc.diagnostics) {
+			d := sc.diagnostics[id]
+			sc.goToPosition(d.Line, d.Column)
+		}
+		sc.problemsList.UnselectAll()
+	}
+
+	sc.problemsPanel = sc.problemsList
+	sc.problemsPanel.Hide()
+	return sc.problemsPanel
+}
+
+// buildStatusBar 构建底部状态栏
+func (sc *GuiController) buildStatusBar() fyne.CanvasObject {
+	sc.problemsButton = widget.NewButtonWithIcon("", theme.InfoIcon(), func() {
+		sc.toggleProblemsPanel()
+	})
+	sc.problemsButton.Importance = widget.LowImportance
+	sc.updateProblemsSummary()
+
+	return container.NewHBox(sc.problemsButton)
+}
+
+// toggleProblemsPanel 展开或收起问题面板
+func (sc *GuiController) toggleProblemsPanel() {
+	if sc.problemsPanel == nil {
+		return
+	}
+	if sc.problemsPanel.Visible() {
+		sc.problemsPanel.Hide()
+	} else {
+		sc.problemsPanel.Show()
+	}
+	sc.editorArea.Refresh()
+}
+
+// runLintNow 立即检查当前内容并展开问题面板
+func (sc *GuiController) runLintNow() {
+	sc.runLint()
+	if sc.problemsPanel != nil && !sc.problemsPanel.Visible() {
+		sc.toggleProblemsPanel()
+	}
+}
+
+// runLint 在后台检查当前内容，完成后在 UI 线程中更新问题面板
+func (sc *GuiController) runLint() {
+	content := sc.appState.GetCurrentContent()
+	dir := filepath.Dir(sc.appState.GetCurrentFile())
+	if sc.appState.GetCurrentFile() == "" && sc.workspace != nil {
+		dir = sc.workspace.GetRoot()
+	}
+
+	// 只应用最后一次检查的结果
+	seq := atomic.AddUint64(&sc.lintSeq, 1)
+
+	go func() {
+		diagnostics := sc.linters.forDir(dir).Lint(content)
+		fyne.Do(func() {
+			if atomic.LoadUint64(&sc.lintSeq) != seq {
+				return
+			}
+			sc.diagnostics = diagnostics
+			if sc.problemsList != nil {
+				sc.problemsList.Refresh()
+			}
+			sc.updateProblemsSummary()
+		})
+	}()
+}
+
+// linterCache 按目录缓存检查器，避免每次输入后的检查都重新查找和读取配置文件
+// 零值可以直接使用；检查在后台进行，因此需要加锁
+type linterCache struct {
+	mutex   sync.Mutex
+	linters map[string]*lint.Linter // 目录 -> 检查器
+}
+
+// forDir 获取目录对应的检查器，第一次使用时按目录中的配置文件创建，配置无效时使用默认配置
+func (c *linterCache) forDir(dir string) *lint.Linter {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if linter, ok := c.linters[dir]; ok {
+		return linter
+	}
+	linter := lint.NewLinter(nil)
+	if path := lint.FindConfig(dir); path != "" {
+		if config, err := lint.LoadConfig(path); err == nil {
+			linter = lint.NewLinter(config)
+		}
+	}
+	if c.linters == nil {
+		c.linters = make(map[string]*lint.Linter)
+	}
+	c.linters[dir] = linter
+	return linter
+}
+
+// reset 清空缓存，下次检查时重新读取配置。在打开和保存文件时调用，使修改后的配置生效
+func (c *linterCache) reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.linters = nil
+}
+
+// clearDiagnostics 清空问题面板，并丢弃尚未完成的检查结果
+func (sc *GuiController) clearDiagnostics() {
+	atomic.AddUint64(&sc.lintSeq, 1)
+	sc.diagnostics = nil
+	if sc.problemsList != nil {
+		sc.problemsList.Refresh()
+	}
+	sc.updateProblemsSummary()
+}
+
+// updateProblemsSummary 更新状态栏中的问题数量
+func (sc *GuiController) updateProblemsSummary() {
+	if sc.problemsButton == nil {
+		return
+	}
+
+	counts := make(map[lint.Severity]int)
+	for _, d := range sc.diagnostics {
+		counts[d.Severity]++
+	}
+
+	var parts []string
+	if n := counts[lint.SeverityError]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个错误", n))
+	}
+	if n := counts[lint.SeverityWarning]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个警告", n))
+	}
+	if n := counts[lint.SeverityInfo]; n > 0 {
+		parts = append(parts, fmt.Sprintf("%d 个提示", n))
+	}
+
+	if len(parts) == 0 {
+		sc.problemsButton.SetText("没有问题")
+		sc.problemsButton.SetIcon(theme.ConfirmIcon())
+		return
+	}
+	sc.problemsButton.SetText(strings.Join(parts, "  "))
+	if counts[lint.SeverityError] > 0 {
+		sc.problemsButton.SetIcon(theme.ErrorIcon())
+	} else {
+		sc.problemsButton.SetIcon(theme.WarningIcon())
+	}
+}
+
+// severityIcon 获取严重级别对应的图标
+func severityIcon(severity lint.Severity) fyne.Resource {
+	switch severity {
+	case lint.SeverityError:
+		return theme.ErrorIcon()
+	case lint.SeverityWarning:
+		return theme.WarningIcon()
+	default:
+		return theme.InfoIcon()
+	}
+}
@@ -0,0 +1,311 @@
+package ui
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// markdownEditor 编辑器组件，在 widget.Entry 的基础上把窗口级快捷键交给控制器处理，
+// 否则编辑器获得焦点时 Fyne 不会把快捷键传递给画布
+type markdownEditor struct {
+	widget.Entry
+	onShortcut func(fyne.Shortcut) bool // 返回 true 表示快捷键已被处理
+	history    editHistory              // 撤销/重做历史
+
+	// 选区起点（选中文本时固定不动的一端）的行列位置，widget.Entry 没有公开它，只能在选区出现时记录
+	anchorRow, anchorColumn int
+	hasAnchor               bool
+}
+
+// newMarkdownEditor 创建新的多行编辑器
+// 不自动换行并由外层滚动容器负责滚动，使编辑器的行与源文件的行一一对应：
+// 自动换行时 CursorRow 是换行后的显示行，大纲、查找和问题面板按源文件行号定位都会出错
+func newMarkdownEditor() *markdownEditor {
+	e := &markdownEditor{}
+	e.MultiLine = true
+	e.Wrapping = fyne.TextWrapOff
+	e.Scroll = container.ScrollNone
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedShortcut 优先执行窗口级快捷键，其余交给 widget.Entry 处理
+func (e *markdownEditor) TypedShortcut(shortcut fyne.Shortcut) {
+	if e.onShortcut != nil && e.onShortcut(shortcut) {
+		return
+	}
+	e.trackSelection(func() { e.Entry.TypedShortcut(shortcut) })
+}
+
+// 以下事件都可能开始或取消选区，交给 widget.Entry 处理的同时记录选区起点
+
+// KeyDown 处理按下修饰键
+func (e *markdownEditor) KeyDown(key *fyne.KeyEvent) {
+	e.trackSelection(func() { e.Entry.KeyDown(key) })
+}
+
+// TypedKey 处理方向键等按键
+func (e *markdownEditor) TypedKey(key *fyne.KeyEvent) {
+	e.trackSelection(func() { e.Entry.TypedKey(key) })
+}
+
+// TypedRune 处理输入的字符
+func (e *markdownEditor) TypedRune(r rune) {
+	e.trackSelection(func() { e.Entry.TypedRune(r) })
+}
+
+// MouseDown 处理按下鼠标
+func (e *markdownEditor) MouseDown(m *desktop.MouseEvent) {
+	e.trackSelection(func() { e.Entry.MouseDown(m) })
+}
+
+// MouseUp 处理松开鼠标
+func (e *markdownEditor) MouseUp(m *desktop.MouseEvent) {
+	e.trackSelection(func() { e.Entry.MouseUp(m) })
+}
+
+// Dragged 处理拖动选择
+func (e *markdownEditor) Dragged(d *fyne.DragEvent) {
+	e.trackSelection(func() { e.Entry.Dragged(d) })
+}
+
+// DoubleTapped 处理双击选择单词
+func (e *markdownEditor) DoubleTapped(p *fyne.PointEvent) {
+	e.trackSelection(func() { e.Entry.DoubleTapped(p) })
+}
+
+// trackSelection 执行事件并更新选区起点：选区出现时起点为事件之前的光标位置，选区消失时清除起点
+// 双击、全选等直接设置选区的操作记录的起点可能不准确，selectionRange 会检查起点是否在选区的一端
+func (e *markdownEditor) trackSelection(event func()) {
+	hadSelection := e.SelectedText() != ""
+	row, column := e.CursorRow, e.CursorColumn
+	event()
+
+	switch {
+	case e.SelectedText() == "":
+		e.hasAnchor = false
+	case !hadSelection:
+		e.anchorRow, e.anchorColumn, e.hasAnchor = row, column, true
+	}
+}
+
+// wrapSelection 用 marker 包围选中的文本（如用 ** 加粗），没有选中文本时插入一对 marker 并把光标放在中间
+// 通过粘贴操作修改内容，使修改可以撤销
+func (e *markdownEditor) wrapSelection(marker string) {
+	selected := e.SelectedText()
+	e.insertText(marker + selected + marker)
+	if selected == "" {
+		e.CursorColumn -= len([]rune(marker))
+		e.Refresh()
+	}
+}
+
+// insertText 在光标处插入文本（替换选中的文本），通过粘贴操作修改内容，使修改可以撤销
+func (e *markdownEditor) insertText(text string) {
+	e.Entry.TypedShortcut(&fyne.ShortcutPaste{Clipboard: &textClipboard{text: text}})
+}
+
+// insertTable 在光标处插入两列的表格模板，光标不在行首时先换行
+func (e *markdownEditor) insertTable() {
+	table := "| 列1 | 列2 |\n| --- | --- |\n|  |  |\n"
+	if e.CursorColumn > 0 {
+		table = "\n" + table
+	}
+	e.insertText(table)
+}
+
+// textClipboard 只保存一段文本的剪贴板，用于通过粘贴操作插入文本而不影响系统剪贴板
+type textClipboard struct {
+	text string
+}
+
+// Content 返回剪贴板内容
+func (c *textClipboard) Content() string {
+	return c.text
+}
+
+// SetContent 设置剪贴板内容
+func (c *textClipboard) SetContent(content string) {
+	c.text = content
+}
+
+// cursorOffset 获取光标在文本中的字节偏移
+func (e *markdownEditor) cursorOffset() int {
+	return offsetAt(e.Text, e.CursorRow, e.CursorColumn)
+}
+
+// setCursorOffset 把光标移动到文本中的字节偏移处
+func (e *markdownEditor) setCursorOffset(offset int) {
+	e.CursorRow, e.CursorColumn = positionAt(e.Text, offset)
+	e.Refresh()
+}
+
+// selectionRange 获取选中文本的字节范围，没有选中文本时返回 false
+// 光标在选区的一端，选区起点在另一端；没有记录起点或起点不在选区的一端时，根据光标前后的文本推断
+func (e *markdownEditor) selectionRange() (start, end int, ok bool) {
+	selected := e.SelectedText()
+	if selected == "" {
+		return 0, 0, false
+	}
+	cursor := e.cursorOffset()
+	before := cursor >= len(selected) && e.Text[cursor-len(selected):cursor] == selected
+	after := cursor+len(selected) <= len(e.Text) && e.Text[cursor:cursor+len(selected)] == selected
+
+	if e.hasAnchor {
+		anchor := offsetAt(e.Text, e.anchorRow, e.anchorColumn)
+		if before && anchor == cursor-len(selected) {
+			return anchor, cursor, true
+		}
+		if after && anchor == cursor+len(selected) {
+			return cursor, anchor, true
+		}
+	}
+	if before {
+		return cursor - len(selected), cursor, true
+	}
+	if after {
+		return cursor, cursor + len(selected), true
+	}
+	return 0, 0, false
+}
+
+// offsetAt 把行列位置（从0开始，列按字符计）转换为文本中的字节偏移
+func offsetAt(text string, row, col int) int {
+	offset := 0
+	for i := 0; i < row; i++ {
+		next := strings.IndexByte(text[offset:], '\n')
+		if next < 0 {
+			return len(text)
+		}
+		offset += next + 1
+	}
+	for i := 0; i < col && offset < len(text) && text[offset] != '\n'; i++ {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// positionAt 把文本中的字节偏移转换为行列位置（从0开始，列按字符计）
+func positionAt(text string, offset int) (row, col int) {
+	offset = min(max(offset, 0), len(text))
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	return strings.Count(text[:offset], "\n"), utf8.RuneCountInString(text[lineStart:offset])
+}
+
+// textLineCount 获取编辑器内容的行数
+func (e *markdownEditor) textLineCount() int {
+	return strings.Count(e.Text, "\n") + 1
+}
+
+// editorLineHeight 获取编辑器中每一行的高度
+func (sc *GuiController) editorLineHeight() float32 {
+	return fyne.MeasureText("M", theme.TextSize(), sc.editorEntry.TextStyle).Height
+}
+
+// goToLine 将编辑器光标移动到指定行（从1开始）并滚动到该行
+func (sc *GuiController) goToLine(line int) {
+	sc.goToPosition(line, 1)
+}
+
+// goToPosition 将编辑器光标移动到指定行列（均从1开始）并滚动到该行
+func (sc *GuiController) goToPosition(line, column int) {
+	if !sc.hasDocument() {
+		return
+	}
+
+	// 限制行号范围
+	lines := strings.Split(sc.editorEntry.Text, "\n")
+	row := line - 1
+	if row >= len(lines) {
+		row = len(lines) - 1
+	}
+	if row < 0 {
+		row = 0
+	}
+
+	// 限制列号范围
+	col := column - 1
+	if length := len([]rune(lines[row])); col > length {
+		col = length
+	}
+	if col < 0 {
+		col = 0
+	}
+
+	sc.editorEntry.CursorRow = row
+	sc.editorEntry.CursorColumn = col
+	sc.editorEntry.Refresh()
+	sc.window.Canvas().Focus(sc.editorEntry)
+
+	// 将目标行滚动到可视区域顶部
+	sc.editorScroll.ScrollToOffset(fyne.NewPos(0, float32(row)*sc.editorLineHeight()))
+}
+
+// ensureCursorVisible 滚动编辑器使光标保持在可视区域内
+func (sc *GuiController) ensureCursorVisible() {
+	if !sc.hasDocument() {
+		return
+	}
+
+	padding := theme.InnerPadding()
+	lineHeight := sc.editorLineHeight()
+	viewport := sc.editorScroll.Size()
+	offset := sc.editorScroll.Offset
+
+	// 垂直方向
+	top := padding + float32(sc.editorEntry.CursorRow)*lineHeight
+	bottom := top + lineHeight + padding
+	if top-padding < offset.Y {
+		offset.Y = top - padding
+	} else if bottom > offset.Y+viewport.Height {
+		offset.Y = bottom - viewport.Height
+	}
+
+	// 水平方向
+	lines := strings.Split(sc.editorEntry.Text, "\n")
+	if sc.editorEntry.CursorRow < len(lines) {
+		runes := []rune(lines[sc.editorEntry.CursorRow])
+		column := sc.editorEntry.CursorColumn
+		if column > len(runes) {
+			column = len(runes)
+		}
+		x := padding + fyne.MeasureText(string(runes[:column]), theme.TextSize(), sc.editorEntry.TextStyle).Width
+		if x-padding < offset.X {
+			offset.X = x - padding
+		} else if x+padding > offset.X+viewport.Width {
+			offset.X = x + padding - viewport.Width
+		}
+	}
+
+	if offset != sc.editorScroll.Offset {
+		sc.editorScroll.ScrollToOffset(offset)
+	}
+}
+
+// selectRange 选中文本中从 start 到 end 的字节范围，光标停在选区末尾
+// widget.Entry 没有公开设置选区的方法，只能模拟按住 Shift 向右移动光标
+func (e *markdownEditor) selectRange(start, end int) {
+	// 先取消原有的选区
+	if e.SelectedText() != "" {
+		e.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+	}
+	e.setCursorOffset(start)
+	if end <= start {
+		return
+	}
+
+	e.Entry.KeyDown(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+	for i := utf8.RuneCountInString(e.Text[start:end]); i > 0; i-- {
+		e.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyRight})
+	}
+	e.Entry.KeyUp(&fyne.KeyEvent{Name: desktop.KeyShiftLeft})
+	e.anchorRow, e.anchorColumn = positionAt(e.Text, start)
+	e.hasAnchor = true
+}
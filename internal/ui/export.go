@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/core"
+	"markup/internal/markdown"
+)
+
+// 导出位置选项
+const (
+	exportBesideSource = "源文件旁"
+	exportChoosePath   = "选择位置..."
+)
+
+// showExportDialog 显示导出 HTML 对话框
+func (sc *GuiController) showExportDialog() {
+	content := sc.appState.GetCurrentContent()
+	currentFile := sc.appState.GetCurrentFile()
+
+	// 页面标题，默认使用第一个一级标题
+	titleEntry := widget.NewEntry()
+	titleEntry.SetPlaceHolder("默认使用第一个一级标题")
+	titleEntry.SetText(sc.mdRenderer.DocumentTitle(content))
+
+	tocCheck := widget.NewCheck("包含目录", nil)
+	selfContainedCheck := widget.NewCheck("内嵌本地图片，生成单个文件", nil)
+
+	// 未保存过的文件只能选择位置导出
+	locationOptions := []string{exportBesideSource, exportChoosePath}
+	if currentFile == "" {
+		locationOptions = []string{exportChoosePath}
+	}
+	locationRadio := widget.NewRadioGroup(locationOptions, nil)
+	locationRadio.Required = true
+	locationRadio.SetSelected(locationOptions[0])
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("标题", titleEntry),
+		widget.NewFormItem("目录", tocCheck),
+		widget.NewFormItem("图片", selfContainedCheck),
+		widget.NewFormItem("保存到", locationRadio),
+	}
+
+	dialog.ShowForm("导出 HTML", "导出", "取消", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		// 相对图片路径以源文件所在目录为基准
+		baseDir := ""
+		if currentFile != "" {
+			baseDir = filepath.Dir(currentFile)
+		}
+
+		result := sc.mdRenderer.ExportHTML(content, markdown.ExportOptions{
+			Title:         strings.TrimSpace(titleEntry.Text),
+			IncludeTOC:    tocCheck.Checked,
+			SelfContained: selfContainedCheck.Checked,
+			BaseDir:       baseDir,
+		})
+
+		if locationRadio.Selected == exportBesideSource {
+			sc.writeExport(htmlPathFor(currentFile), result)
+		} else {
+			sc.chooseExportPath(currentFile, result)
+		}
+	}, sc.window)
+}
+
+// chooseExportPath 选择导出位置并写入 HTML
+func (sc *GuiController) chooseExportPath(currentFile string, result *markdown.ExportResult) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		writer.Close()
+
+		sc.writeExport(writer.URI().Path(), result)
+	}, sc.window)
+
+	// 默认文件名和目录与源文件一致
+	if currentFile != "" {
+		saveDialog.SetFileName(filepath.Base(htmlPathFor(currentFile)))
+		if dir, err := storage.ListerForURI(storage.NewFileURI(filepath.Dir(currentFile))); err == nil {
+			saveDialog.SetLocation(dir)
+		}
+	} else {
+		saveDialog.SetFileName("untitled.html")
+	}
+	saveDialog.Show()
+}
+
+// writeExport 写入导出的 HTML 文件并报告结果
+// 导出的文件可以随时重新生成，不按保存文档的设置保留 .bak 备份
+func (sc *GuiController) writeExport(path string, result *markdown.ExportResult) {
+	if err := core.WriteFileAtomic(path, []byte(result.HTML), false); err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
+
+	message := "已导出到 " + path
+	if len(result.InlinedImages) > 0 {
+		message += fmt.Sprintf("\n已内嵌 %d 张图片", len(result.InlinedImages))
+	}
+	if len(result.MissingImages) > 0 {
+		message += fmt.Sprintf("\n以下 %d 张图片无法读取，保留了原始引用：\n%s",
+			len(result.MissingImages), strings.Join(result.MissingImages, "\n"))
+	}
+	if len(result.SkippedImages) > 0 {
+		message += fmt.Sprintf("\n以下 %d 个文件不在文档所在目录下或不是图片，没有内嵌：\n%s",
+			len(result.SkippedImages), strings.Join(result.SkippedImages, "\n"))
+	}
+	dialog.ShowInformation("导出成功", message, sc.window)
+}
+
+// htmlPathFor 获取与源文件同名的 HTML 文件路径
+func htmlPathFor(sourcePath string) string {
+	return strings.TrimSuffix(sourcePath, filepath.Ext(sourcePath)) + ".html"
+}
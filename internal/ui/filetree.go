@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/workspace"
+)
+
+// openFolder 打开文件夹作为工作区
+func (sc *GuiController) openFolder() {
+	dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+		if err != nil || uri == nil {
+			return
+		}
+
+		sc.loadWorkspace(uri.Path())
+	}, sc.window)
+}
+
+// loadWorkspace 遍历指定目录并在左侧显示文件树
+func (sc *GuiController) loadWorkspace(root string) {
+	ws, err := workspace.NewWorkspace(root)
+	if err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
+	sc.workspace = ws
+	defer sc.addRecent(ws.GetRoot(), true)
+
+	// 首次进入编辑模式时构建编辑界面
+	if !sc.isEditing {
+		sc.isEditing = true
+		sc.window.SetContent(sc.BuildUI(sc.window))
+		sc.updateWindowTitle()
+		return
+	}
+
+	// 已在编辑界面时只刷新文件树，并清空上一个工作区的搜索结果
+	sc.cancelWorkspaceSearch()
+	sc.clearSearchResults()
+	sc.fileTreeTitle.SetText(filepath.Base(ws.GetRoot()))
+	sc.fileTree.UnselectAll()
+	sc.fileTree.Refresh()
+	sc.sidebar.Show()
+	sc.updateWindowTitle()
+}
+
+// buildFileTree 构建左侧文件树
+func (sc *GuiController) buildFileTree() fyne.CanvasObject {
+	sc.fileTree = widget.NewTree(
+		// 子节点
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			if sc.workspace == nil {
+				return nil
+			}
+			return sc.workspace.Children(uid)
+		},
+		// 是否为目录
+		func(uid widget.TreeNodeID) bool {
+			return sc.workspace != nil && sc.workspace.IsDir(uid)
+		},
+		// 创建节点
+		func(branch bool) fyne.CanvasObject {
+			icon := theme.DocumentIcon()
+			if branch {
+				icon = theme.FolderIcon()
+			}
+			return container.NewHBox(widget.NewIcon(icon), widget.NewLabel(""))
+		},
+		// 更新节点
+		func(uid widget.TreeNodeID, branch bool, obj fyne.CanvasObject) {
+			label := obj.(*fyne.Container).Objects[1].(*widget.Label)
+			label.SetText(filepath.Base(uid))
+		},
+	)
+
+	// 点击文件时在标签页中打开
+	sc.fileTree.OnSelected = func(uid widget.TreeNodeID) {
+		if sc.workspace == nil || sc.workspace.IsDir(uid) {
+			return
+		}
+		currentFile := sc.appState.GetCurrentFile()
+		if uid == currentFile {
+			return
+		}
+
+		sc.loadFile(uid)
+
+		// 加载失败时恢复原来的选中项
+		if sc.appState.GetCurrentFile() != uid {
+			sc.selectFileInTree(currentFile)
+		}
+	}
+
+	// 标题显示工作区目录名
+	sc.fileTreeTitle = widget.NewLabel("文件")
+	sc.fileTreeTitle.TextStyle = fyne.TextStyle{Bold: true}
+	if sc.workspace != nil {
+		sc.fileTreeTitle.SetText(filepath.Base(sc.workspace.GetRoot()))
+	}
+
+	// 刷新按钮：重新遍历工作区，显示在外部新建或删除的文件
+	refreshBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), sc.refreshWorkspace)
+	refreshBtn.Importance = widget.LowImportance
+	header := container.NewBorder(nil, nil, nil, refreshBtn, sc.fileTreeTitle)
+
+	return container.NewBorder(header, nil, nil, nil, sc.fileTree)
+}
+
+// refreshWorkspace 重新遍历工作区目录并刷新文件树，保留当前文件的选中状态
+func (sc *GuiController) refreshWorkspace() {
+	if sc.workspace == nil || sc.fileTree == nil {
+		return
+	}
+	if err := sc.workspace.Scan(); err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
+	sc.fileTree.Refresh()
+	sc.selectFileInTree(sc.appState.GetCurrentFile())
+}
+
+// selectFileInTree 在文件树中选中指定文件，文件不在工作区内时取消选中
+func (sc *GuiController) selectFileInTree(filePath string) {
+	if sc.fileTree == nil || sc.workspace == nil {
+		return
+	}
+	if filePath == "" || !strings.HasPrefix(filePath, sc.workspace.GetRoot()+string(filepath.Separator)) {
+		sc.fileTree.UnselectAll()
+		return
+	}
+	sc.fileTree.Select(filePath)
+}
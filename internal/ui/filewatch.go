@@ -0,0 +1,173 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/core"
+)
+
+// fileWatchInterval 检查外部修改的间隔
+const fileWatchInterval = 2 * time.Second
+
+// startFileWatch 定时检查当前文件是否被外部修改，只启动一次
+func (sc *GuiController) startFileWatch() {
+	if sc.fileWatchStarted {
+		return
+	}
+	sc.fileWatchStarted = true
+
+	go func() {
+		ticker := time.NewTicker(fileWatchInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fyne.Do(sc.checkExternalChange)
+		}
+	}()
+}
+
+// buildChangeBanner 构建文件被外部修改时显示的提示条
+func (sc *GuiController) buildChangeBanner() fyne.CanvasObject {
+	sc.changeMessage = widget.NewLabel("")
+
+	sc.changeReload = widget.NewButton("重新加载", func() {
+		sc.reloadFromDisk()
+	})
+	keepBtn := widget.NewButton("保留我的版本", func() {
+		sc.keepLocalVersion()
+	})
+	sc.changeCompare = widget.NewButton("比较", func() {
+		sc.showDiskDiff()
+	})
+	actions := container.NewHBox(sc.changeReload, keepBtn, sc.changeCompare)
+
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameHover))
+	content := container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), actions, sc.changeMessage)
+
+	sc.changeBanner = container.NewStack(background, content)
+	sc.changeBanner.Hide()
+	return sc.changeBanner
+}
+
+// checkExternalChange 检查当前文件是否被外部修改
+// 没有本地修改时自动重新加载，否则显示提示条让用户选择
+func (sc *GuiController) checkExternalChange() {
+	if !sc.hasDocument() || sc.changeBanner == nil || sc.changeBanner.Visible() {
+		return
+	}
+
+	changed, snapshot, err := sc.appState.CheckDiskChange()
+	if err != nil || !changed {
+		return
+	}
+	sc.pendingSnapshot = snapshot
+
+	if !snapshot.Exists {
+		sc.showChangeBanner("文件已在磁盘上被删除或移动，保存时会重新创建。", false)
+		return
+	}
+	if !sc.appState.HasUnsavedChanges() {
+		sc.reloadFromDisk()
+		return
+	}
+	sc.showChangeBanner("文件已被其他程序修改，而这里还有未保存的更改。", true)
+}
+
+// showChangeBanner 显示外部修改提示条，canReload 为 false 时只能保留当前版本
+func (sc *GuiController) showChangeBanner(message string, canReload bool) {
+	sc.changeMessage.SetText(message)
+	// 重新加载和比较需要磁盘上有文件
+	for _, action := range []*widget.Button{sc.changeReload, sc.changeCompare} {
+		if canReload {
+			action.Show()
+		} else {
+			action.Hide()
+		}
+	}
+	sc.changeBanner.Show()
+}
+
+// hideChangeBanner 隐藏外部修改提示条
+func (sc *GuiController) hideChangeBanner() {
+	if sc.changeBanner != nil {
+		sc.changeBanner.Hide()
+	}
+}
+
+// reloadFromDisk 从磁盘重新加载当前文件，放弃本地修改，尽量保持光标所在行
+func (sc *GuiController) reloadFromDisk() {
+	currentFile := sc.appState.GetCurrentFile()
+	content, err := sc.appState.LoadFile(currentFile)
+	if err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
+
+	// 尽量保持光标所在行，重新加载可以撤销
+	row := min(sc.editorEntry.CursorRow, strings.Count(content, "\n"))
+	sc.appState.SetCurrentContent(content)
+	sc.appState.SetOriginalContent(content)
+	sc.appState.UpdateDiskSnapshot()
+	sc.editorEntry.replaceText(content, row, 0)
+
+	sc.discardDraft(sc.current)
+	sc.hideChangeBanner()
+	sc.updateWindowTitle()
+}
+
+// keepLocalVersion 保留本地内容，并以新的磁盘内容作为比较基准（之后保存会覆盖磁盘文件）
+func (sc *GuiController) keepLocalVersion() {
+	if sc.pendingSnapshot.Exists {
+		if content, err := sc.appState.LoadFile(sc.appState.GetCurrentFile()); err == nil {
+			sc.appState.SetOriginalContent(content)
+		}
+	}
+	sc.appState.SetDiskSnapshot(sc.pendingSnapshot)
+	sc.hideChangeBanner()
+	sc.updateWindowTitle()
+}
+
+// showDiskDiff 显示磁盘版本与当前内容的差异
+func (sc *GuiController) showDiskDiff() {
+	diskContent, err := sc.appState.LoadFile(sc.appState.GetCurrentFile())
+	if err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
+
+	diff := core.UnifiedDiff("磁盘上的版本", "我的版本", diskContent, sc.appState.GetCurrentContent(), 3)
+	if diff == "" {
+		diff = "内容相同"
+	}
+
+	diffView := widget.NewMultiLineEntry()
+	diffView.SetText(diff)
+	diffView.TextStyle = fyne.TextStyle{Monospace: true}
+	diffView.Wrapping = fyne.TextWrapOff
+	diffView.Disable()
+
+	var diffDialog *dialog.CustomDialog
+	reloadBtn := widget.NewButton("使用磁盘版本", func() {
+		diffDialog.Hide()
+		sc.reloadFromDisk()
+	})
+	keepBtn := widget.NewButton("保留我的版本", func() {
+		diffDialog.Hide()
+		sc.keepLocalVersion()
+	})
+	closeBtn := widget.NewButton("关闭", func() {
+		diffDialog.Hide()
+	})
+
+	diffDialog = dialog.NewCustomWithoutButtons("比较更改", diffView, sc.window)
+	diffDialog.SetButtons([]fyne.CanvasObject{closeBtn, keepBtn, reloadBtn})
+	diffDialog.Resize(fyne.NewSize(800, 600))
+	diffDialog.Show()
+}
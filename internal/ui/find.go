@@ -0,0 +1,465 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/search"
+)
+
+// maxFindHighlights 最多高亮的匹配数，匹配过多时只高亮前面的部分，避免创建过多的图形对象
+const maxFindHighlights = 1000
+
+// findBar 编辑器上方的查找/替换栏
+type findBar struct {
+	bar        *fyne.Container // 整个查找栏
+	replaceRow *fyne.Container // 替换行，只在替换模式下显示
+
+	query       *findEntry    // 查找内容
+	replacement *findEntry    // 替换内容
+	regex       *widget.Check // 正则表达式
+	matchCase   *widget.Check // 区分大小写
+	wholeWord   *widget.Check // 全词匹配
+	inSelection *widget.Check // 只在选中的文本中查找
+	status      *widget.Label // 匹配数量或错误信息
+
+	matcher *search.Matcher // 当前的查找条件，查找内容为空或有误时为 nil
+	matches []search.Match  // 当前文档中的匹配
+	current int             // 当前匹配的序号，没有时为 -1
+
+	hasSelection bool // 打开查找栏时编辑器中是否有选中的文本
+	scopeStart   int  // 选区的起点（字节偏移）
+	scopeEnd     int  // 选区的终点（字节偏移）
+}
+
+// visible 判断查找栏是否显示
+func (f *findBar) visible() bool {
+	return f != nil && f.bar.Visible()
+}
+
+// options 获取当前的查找选项
+func (f *findBar) options() search.Options {
+	return search.Options{
+		Regex:     f.regex.Checked,
+		MatchCase: f.matchCase.Checked,
+		WholeWord: f.wholeWord.Checked,
+	}
+}
+
+// findEntry 查找栏中的输入框，按 Esc 关闭查找栏
+type findEntry struct {
+	widget.Entry
+	onEscape func()
+}
+
+// newFindEntry 创建查找栏中的单行输入框
+func newFindEntry(placeHolder string) *findEntry {
+	e := &findEntry{}
+	e.SetPlaceHolder(placeHolder)
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedKey 按 Esc 时关闭查找栏，其余按键交给 widget.Entry 处理
+func (e *findEntry) TypedKey(key *fyne.KeyEvent) {
+	if key.Name == fyne.KeyEscape && e.onEscape != nil {
+		e.onEscape()
+		return
+	}
+	e.Entry.TypedKey(key)
+}
+
+// buildFindBar 构建查找/替换栏，默认隐藏
+func (sc *GuiController) buildFindBar() fyne.CanvasObject {
+	f := &findBar{current: -1}
+	sc.find = f
+
+	f.query = newFindEntry("查找")
+	f.query.OnChanged = func(string) {
+		sc.updateFind()
+	}
+	// 回车查找下一个
+	f.query.OnSubmitted = func(string) {
+		sc.findStep(1)
+	}
+	f.query.onEscape = sc.hideFind
+
+	f.replacement = newFindEntry("替换")
+	f.replacement.OnSubmitted = func(string) {
+		sc.replaceCurrent()
+	}
+	f.replacement.onEscape = sc.hideFind
+
+	onOptionChanged := func(bool) {
+		sc.updateFind()
+	}
+	f.regex = widget.NewCheck("正则", onOptionChanged)
+	f.matchCase = widget.NewCheck("区分大小写", onOptionChanged)
+	f.wholeWord = widget.NewCheck("全词匹配", onOptionChanged)
+	f.inSelection = widget.NewCheck("仅选区", func(checked bool) {
+		// 没有选中文本时不能只在选区中查找
+		if checked && !f.hasSelection {
+			f.inSelection.SetChecked(false)
+			f.status.SetText("没有选中的文本")
+			return
+		}
+		sc.updateFind()
+	})
+	f.status = widget.NewLabel("")
+
+	previousBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+		sc.findStep(-1)
+	})
+	nextBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+		sc.findStep(1)
+	})
+	closeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), sc.hideFind)
+	findActions := container.NewHBox(f.regex, f.matchCase, f.wholeWord, f.inSelection, f.status, previousBtn, nextBtn, closeBtn)
+
+	replaceBtn := widget.NewButton("替换", sc.replaceCurrent)
+	replaceAllBtn := widget.NewButton("全部替换", sc.replaceAll)
+	f.replaceRow = container.NewBorder(nil, nil, nil, container.NewHBox(replaceBtn, replaceAllBtn), f.replacement)
+
+	f.bar = container.NewVBox(
+		container.NewBorder(nil, nil, nil, findActions, f.query),
+		f.replaceRow,
+	)
+	f.bar.Hide()
+	return f.bar
+}
+
+// showFind 显示查找栏，replace 为 true 时同时显示替换行
+// 编辑器中选中了单行文本时用它作为查找内容
+func (sc *GuiController) showFind(replace bool) {
+	f := sc.find
+	if f == nil {
+		return
+	}
+
+	// 记录选区，用于只在选区中查找
+	start, end, ok := sc.editorEntry.selectionRange()
+	f.hasSelection = ok
+	if ok {
+		f.scopeStart, f.scopeEnd = start, end
+	}
+	if !ok && f.inSelection.Checked {
+		f.inSelection.SetChecked(false)
+	}
+	if selected := sc.editorEntry.SelectedText(); selected != "" && !strings.Contains(selected, "\n") {
+		f.query.SetText(selected)
+	}
+
+	if replace {
+		f.replaceRow.Show()
+	} else {
+		f.replaceRow.Hide()
+	}
+	f.bar.Show()
+	sc.updateFind()
+
+	sc.window.Canvas().Focus(f.query)
+}
+
+// hideFind 隐藏查找栏并清除高亮，焦点回到编辑器
+func (sc *GuiController) hideFind() {
+	f := sc.find
+	if !f.visible() {
+		return
+	}
+	f.bar.Hide()
+	f.matches = nil
+	f.current = -1
+	sc.renderFindHighlights()
+
+	if sc.hasDocument() {
+		sc.window.Canvas().Focus(sc.editorEntry)
+	}
+}
+
+// updateFind 在当前文档中重新查找，并刷新匹配数量和高亮
+func (sc *GuiController) updateFind() {
+	f := sc.find
+	if !f.visible() || !sc.hasDocument() {
+		return
+	}
+	f.matcher = nil
+	f.matches = nil
+	f.current = -1
+
+	matcher, err := search.Compile(f.query.Text, f.options())
+	switch {
+	case errors.Is(err, search.ErrEmptyQuery):
+		f.status.SetText("")
+	case err != nil:
+		f.status.SetText("正则表达式有误")
+	default:
+		f.matcher = matcher
+		f.matches = sc.findInScope(matcher)
+
+		// 从光标所在的位置开始计算当前匹配
+		cursor := sc.editorEntry.cursorOffset()
+		for i, match := range f.matches {
+			if match.End >= cursor {
+				f.current = i
+				break
+			}
+		}
+		if f.current < 0 && len(f.matches) > 0 {
+			f.current = 0
+		}
+		sc.updateFindStatus()
+	}
+	sc.renderFindHighlights()
+}
+
+// findInScope 查找当前文档中的所有匹配，勾选仅选区时只保留选区内的匹配
+func (sc *GuiController) findInScope(matcher *search.Matcher) []search.Match {
+	f := sc.find
+	text := sc.editorEntry.Text
+	matches := matcher.FindAll(text)
+	if !f.inSelection.Checked {
+		return matches
+	}
+
+	// 选区之后的内容被修改时选区可能超出文本范围
+	scopeEnd := min(f.scopeEnd, len(text))
+	var inScope []search.Match
+	for _, match := range matches {
+		if match.Start >= f.scopeStart && match.End <= scopeEnd {
+			inScope = append(inScope, match)
+		}
+	}
+	return inScope
+}
+
+// updateFindStatus 显示当前是第几个匹配以及匹配总数
+func (sc *GuiController) updateFindStatus() {
+	f := sc.find
+	if len(f.matches) == 0 {
+		f.status.SetText("无结果")
+		return
+	}
+	f.status.SetText(fmt.Sprintf("%d / %d", f.current+1, len(f.matches)))
+}
+
+// findNext 选中下一个匹配，查找栏未显示时先显示查找栏
+func (sc *GuiController) findNext() {
+	sc.findStep(1)
+}
+
+// findPrevious 选中上一个匹配，查找栏未显示时先显示查找栏
+func (sc *GuiController) findPrevious() {
+	sc.findStep(-1)
+}
+
+// findStep 从光标位置开始选中下一个（step 为 1）或上一个（step 为 -1）匹配，到达末尾时从头开始
+func (sc *GuiController) findStep(step int) {
+	f := sc.find
+	if !f.visible() {
+		sc.showFind(false)
+		return
+	}
+	if len(f.matches) == 0 {
+		return
+	}
+
+	// 已选中文本时从选区的起点开始，避免再次选中同一处
+	from := sc.editorEntry.cursorOffset()
+	start, _, selected := sc.editorEntry.selectionRange()
+	if selected {
+		from = start
+	}
+
+	index := -1
+	if step > 0 {
+		if selected {
+			from++
+		}
+		for i, match := range f.matches {
+			if match.Start >= from {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			index = 0
+		}
+	} else {
+		for i := len(f.matches) - 1; i >= 0; i-- {
+			if f.matches[i].Start < from {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			index = len(f.matches) - 1
+		}
+	}
+	sc.selectMatch(index)
+}
+
+// selectMatch 在编辑器中选中指定的匹配并滚动到该处
+func (sc *GuiController) selectMatch(index int) {
+	f := sc.find
+	f.current = index
+	match := f.matches[index]
+	sc.editorEntry.selectRange(match.Start, match.End)
+	sc.ensureCursorVisible()
+	sc.updateFindStatus()
+	sc.renderFindHighlights()
+}
+
+// replaceCurrent 替换当前选中的匹配并选中下一个；当前匹配尚未选中时先选中它
+func (sc *GuiController) replaceCurrent() {
+	f := sc.find
+	if !f.visible() || f.matcher == nil || f.current < 0 {
+		return
+	}
+
+	match := f.matches[f.current]
+	start, end, selected := sc.editorEntry.selectionRange()
+	if !selected || start != match.Start || end != match.End {
+		sc.selectMatch(f.current)
+		return
+	}
+
+	text := sc.editorEntry.Text
+	replacement := f.matcher.Expand(text, match, f.replacement.Text)
+	newText := text[:match.Start] + replacement + text[match.End:]
+	if f.inSelection.Checked {
+		f.scopeEnd += len(newText) - len(text)
+	}
+	row, col := positionAt(newText, match.Start+len(replacement))
+	sc.editorEntry.replaceText(newText, row, col)
+
+	sc.updateFind()
+	if len(f.matches) > 0 {
+		sc.findStep(1)
+	}
+}
+
+// replaceAll 替换所有匹配（勾选仅选区时只替换选区内的匹配），整个替换作为一步撤销
+func (sc *GuiController) replaceAll() {
+	f := sc.find
+	if !f.visible() || f.matcher == nil || len(f.matches) == 0 {
+		return
+	}
+
+	text := sc.editorEntry.Text
+	count := len(f.matches)
+	newText := f.matcher.ReplaceAll(text, f.matches, f.replacement.Text)
+	if f.inSelection.Checked {
+		f.scopeEnd += len(newText) - len(text)
+	}
+	row, col := positionAt(newText, f.matches[0].Start)
+	sc.editorEntry.replaceText(newText, row, col)
+
+	sc.updateFind()
+	f.status.SetText(fmt.Sprintf("已替换 %d 处", count))
+}
+
+// renderFindHighlights 在当前编辑器上方绘制所有匹配的高亮，当前匹配使用不同的颜色
+func (sc *GuiController) renderFindHighlights() {
+	doc := sc.current
+	if doc == nil {
+		return
+	}
+	var rects []fyne.CanvasObject
+	f := sc.find
+	if f.visible() && len(f.matches) > 0 {
+		text := doc.editor.Text
+		style := doc.editor.TextStyle
+		padding := theme.InnerPadding()
+		lineHeight := sc.editorLineHeight()
+		matchColor := withAlpha(theme.Color(theme.ColorNameWarning), 0x60)
+		currentColor := withAlpha(theme.Color(theme.ColorNamePrimary), 0x80)
+
+		// 按顺序扫描文本，避免每处匹配都从头计算行号
+		row, lineStart, scanned := 0, 0, 0
+		advance := func(offset int) {
+			for {
+				next := strings.IndexByte(text[scanned:offset], '\n')
+				if next < 0 {
+					break
+				}
+				row++
+				lineStart = scanned + next + 1
+				scanned = lineStart
+			}
+			scanned = offset
+		}
+		measure := func(s string) float32 {
+			return fyne.MeasureText(s, theme.TextSize(), style).Width
+		}
+
+		for i, match := range f.matches {
+			if i >= maxFindHighlights {
+				break
+			}
+			fill := matchColor
+			if i == f.current {
+				fill = currentColor
+			}
+
+			// 跨行的匹配每行绘制一段
+			advance(match.Start)
+			segmentStart := match.Start
+			for {
+				lineEnd := strings.IndexByte(text[segmentStart:match.End], '\n')
+				segmentEnd := match.End
+				if lineEnd >= 0 {
+					segmentEnd = segmentStart + lineEnd
+				}
+
+				x := padding + measure(text[lineStart:segmentStart])
+				width := measure(text[segmentStart:segmentEnd])
+				if lineEnd >= 0 {
+					width += measure(" ") // 表示换行符
+				}
+				rect := canvas.NewRectangle(fill)
+				rect.Move(fyne.NewPos(x, padding+float32(row)*lineHeight))
+				rect.Resize(fyne.NewSize(width, lineHeight))
+				rects = append(rects, rect)
+
+				if lineEnd < 0 {
+					break
+				}
+				advance(segmentEnd + 1)
+				segmentStart = segmentEnd + 1
+			}
+			advance(match.End)
+		}
+	}
+	doc.highlights.Objects = rects
+	doc.highlights.Refresh()
+}
+
+// withAlpha 获取指定颜色的半透明版本
+func withAlpha(c color.Color, alpha uint8) color.Color {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	nrgba.A = alpha
+	return nrgba
+}
+
+// resetFindScope 切换文档后取消仅选区查找并重新查找
+func (sc *GuiController) resetFindScope() {
+	f := sc.find
+	if !f.visible() {
+		return
+	}
+	f.hasSelection = false
+	if f.inSelection.Checked {
+		// 取消勾选时会重新查找
+		f.inSelection.SetChecked(false)
+		return
+	}
+	sc.updateFind()
+}
@@ -1,42 +1,140 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
 	"markup/internal/core"
+	"markup/internal/lint"
 	"markup/internal/markdown"
+	"markup/internal/workspace"
 )
 
 // GuiController 主界面控制器
 type GuiController struct {
 	window     fyne.Window
 	mdRenderer *markdown.Renderer
-	appState   *core.AppState
+	appState   *core.AppState       // 当前标签页的文档状态
+	settings   *core.Settings       // 用户设置
+	session    *core.Session        // 上次退出时保存的会话
+	drafts     *core.DraftStore     // 草稿存储（无法确定恢复目录时为 nil）
+	journals   *core.JournalStore   // 工作区替换记录（无法确定用户配置目录时为 nil）
+	recent     *core.RecentList     // 最近打开的文件和文件夹（无法确定配置目录时为 nil）
+	workspace  *workspace.Workspace // 当前打开的工作区（未打开文件夹时为 nil）
+
+	// 标签页
+	docTabs   *container.DocTabs // 编辑器标签页
+	documents []*document        // 已打开的文档，顺序与标签页一致
+	current   *document          // 当前标签页的文档
 
 	// UI 组件
-	editorEntry *widget.Entry
+	editorEntry    *markdownEditor    // 当前标签页的编辑器
+	editorScroll   *container.Scroll  // 当前标签页的编辑器滚动容器
+	mainSplit      *container.Split   // 文件树与其余部分的分屏容器
+	contentSplit   *container.Split   // 大纲与编辑区的分屏容器
+	editorSplit    *container.Split   // 编辑器与预览的分屏容器
+	viewModeSelect *widget.RadioGroup // 工具栏中的模式选择
+	fileTree       *widget.Tree       // 左侧文件树
+	fileTreeTitle  *widget.Label      // 文件树标题（工作区目录名）
+	sidebar        fyne.CanvasObject  // 左侧栏容器
+	sidebarTabs    *container.AppTabs // 左侧栏中的文件树和搜索面板
+	search         *searchPanel       // 工作区搜索面板
+	outlineList    *widget.List       // 中间大纲列表
+	previewText    *widget.RichText   // 预览内容
+	previewScroll  *container.Scroll  // 预览滚动容器
+	editorArea     *container.Split   // 编辑区与问题面板的上下分屏
+	problemsList   *widget.List       // 问题列表
+	problemsPanel  fyne.CanvasObject  // 问题面板（可收起）
+	problemsButton *widget.Button     // 状态栏中的问题数量
+	changeBanner   *fyne.Container    // 文件被外部修改时的提示条
+	changeMessage  *widget.Label      // 提示条中的说明
+	changeReload   *widget.Button     // 提示条中的“重新加载”按钮
+	changeCompare  *widget.Button     // 提示条中的“比较”按钮
+	find           *findBar           // 查找/替换栏
 
 	// 状态
-	isEditing bool // 是否处于编辑模式
+	isEditing        bool             // 是否处于编辑模式
+	viewMode         viewMode         // 编辑区显示模式
+	darkTheme        bool             // 是否使用深色配色
+	zoom             float32          // 文字缩放比例
+	commands         *commandRegistry // 命令及其快捷键
+	bindingProblems  []string         // 快捷键配置中的错误和冲突，启动后提示
+	outlineDebouncer *debouncer       // 大纲刷新防抖
+	previewDebouncer *debouncer       // 预览刷新防抖
+	lintDebouncer    *debouncer       // 语法检查防抖
+	findDebouncer    *debouncer       // 查找结果刷新防抖
+
+	diagnostics []lint.Diagnostic // 当前文档的检查结果（仅在 UI 线程访问）
+	lintSeq     uint64            // 检查序号，用于丢弃过期的结果
+	linters     linterCache       // 按目录缓存的检查器
+
+	// 自动保存
+	autosaveStarted bool  // 是否已启动自动保存
+	autosaveFailed  bool  // 自动保存是否失败过（只提示一次）
+	settingsErr     error // 加载用户设置时的错误，启动后提示
+
+	// 外部修改检测
+	fileWatchStarted bool              // 是否已启动检测
+	pendingSnapshot  core.FileSnapshot // 检测到的新磁盘状态，等待用户处理
 }
 
 // NewGuiController 创建新的主控制器
 func NewGuiController() *GuiController {
-	return &GuiController{
-		mdRenderer: markdown.NewRenderer(),
-		appState:   core.NewAppState(),
-		isEditing:  false,
+	settings, settingsErr := core.LoadSettings()
+	drafts, _ := core.NewDraftStore("")
+	journals, _ := core.NewJournalStore("")
+	// 会话文件损坏时从空白状态启动即可，不需要提示
+	session, _ := core.LoadSession()
+	recent, _ := core.LoadRecentList("")
+
+	sc := &GuiController{
+		mdRenderer:  markdown.NewRenderer(),
+		appState:    core.NewAppState(),
+		settings:    settings,
+		session:     session,
+		recent:      recent,
+		drafts:      drafts,
+		journals:    journals,
+		settingsErr: settingsErr,
+		isEditing:   false,
+		zoom:        1,
+
+		outlineDebouncer: newDebouncer(300 * time.Millisecond),
+		previewDebouncer: newDebouncer(200 * time.Millisecond),
+		lintDebouncer:    newDebouncer(500 * time.Millisecond),
+		findDebouncer:    newDebouncer(150 * time.Millisecond),
+	}
+	sc.registerCommands()
+	return sc
+}
+
+// OnStarted 应用启动后的处理：提示设置错误、恢复会话和草稿并开始自动保存
+func (sc *GuiController) OnStarted() {
+	if sc.settingsErr != nil {
+		dialog.ShowError(sc.settingsErr, sc.window)
+	}
+	if len(sc.bindingProblems) > 0 {
+		dialog.ShowInformation("快捷键配置有误", strings.Join(sc.bindingProblems, "\n"), sc.window)
 	}
+	sc.restoreSession()
+	sc.offerDraftRecovery()
+	sc.startAutosave()
+	sc.startFileWatch()
 }
 
 // BuildUI 构建用户界面
 func (sc *GuiController) BuildUI(window fyne.Window) fyne.CanvasObject {
 	sc.window = window
+	sc.registerShortcuts()
+	sc.refreshMainMenu()
 
 	if !sc.isEditing {
 		// 显示启动界面
@@ -66,6 +164,12 @@ func (sc *GuiController) buildStartupUI() fyne.CanvasObject {
 	})
 	openBtn.Resize(fyne.NewSize(200, 50))
 
+	// 打开文件夹按钮
+	openFolderBtn := widget.NewButton("打开文件夹", func() {
+		sc.openFolder()
+	})
+	openFolderBtn.Resize(fyne.NewSize(200, 50))
+
 	// 创建垂直布局
 	content := container.NewVBox(
 		widget.NewLabel(""), // 空白
@@ -75,35 +179,74 @@ func (sc *GuiController) buildStartupUI() fyne.CanvasObject {
 		container.NewCenter(newBtn),
 		widget.NewLabel(""), // 间距
 		container.NewCenter(openBtn),
-		widget.NewLabel(""), // 空白
+		widget.NewLabel(""), // 间距
+		container.NewCenter(openFolderBtn),
 		widget.NewLabel(""), // 空白
 	)
 
+	// 最近打开的文件和文件夹
+	if recentPanel := sc.buildRecentPanel(); recentPanel != nil {
+		content.Add(recentPanel)
+	}
+	content.Add(widget.NewLabel("")) // 空白
+
 	return container.NewCenter(content)
 }
 
+// showStartupUI 关闭所有文档后回到启动界面
+func (sc *GuiController) showStartupUI() {
+	sc.isEditing = false
+	sc.current = nil
+	sc.appState = core.NewAppState()
+	sc.editorEntry = nil
+	sc.editorScroll = nil
+	sc.docTabs = nil
+	sc.cancelWorkspaceSearch()
+
+	sc.window.SetContent(sc.BuildUI(sc.window))
+	sc.updateWindowTitle()
+}
+
 // buildEditorUI 构建编辑界面
 func (sc *GuiController) buildEditorUI() fyne.CanvasObject {
-	// 初始化编辑器
-	sc.editorEntry = widget.NewMultiLineEntry()
-	sc.editorEntry.Wrapping = fyne.TextWrapWord
-	sc.editorEntry.SetPlaceHolder("在此输入 Markdown 内容...")
+	// 创建工具栏
+	toolbar := sc.createEditorToolbar()
 
-	// 设置文本变化事件
-	sc.editorEntry.OnChanged = func(content string) {
-		sc.appState.SetCurrentContent(content)
+	// 创建左侧文件树和搜索面板，未打开文件夹时隐藏
+	sc.sidebarTabs = container.NewAppTabs(
+		container.NewTabItemWithIcon("文件", theme.FolderIcon(), sc.buildFileTree()),
+		container.NewTabItemWithIcon("搜索", theme.SearchIcon(), sc.buildSearchPanel()),
+	)
+	sc.sidebar = sc.sidebarTabs
+	if sc.workspace == nil {
+		sc.sidebar.Hide()
 	}
 
-	// 创建工具栏
-	toolbar := sc.createEditorToolbar()
+	// 右侧编辑器 + 预览
+	sc.editorSplit = container.NewHSplit(sc.buildDocumentTabs(), sc.buildPreviewPanel())
+	sc.editorSplit.Offset = sc.session.SplitOffset(splitEditor, 0.5)
+
+	// 编辑区下方为问题面板
+	sc.editorArea = container.NewVSplit(sc.editorSplit, sc.buildProblemsPanel())
+	sc.editorArea.Offset = sc.session.SplitOffset(splitProblems, 0.8)
+
+	// 中间大纲 + 右侧编辑区
+	sc.contentSplit = container.NewHSplit(sc.buildOutlinePanel(), sc.editorArea)
+	sc.contentSplit.Offset = sc.session.SplitOffset(splitOutline, 0.2)
+
+	sc.mainSplit = container.NewHSplit(sc.sidebar, sc.contentSplit)
+	sc.mainSplit.Offset = sc.session.SplitOffset(splitSidebar, 0.2)
+
+	// 应用当前显示模式
+	sc.applyViewMode()
 
 	// 创建主布局
 	return container.NewBorder(
-		toolbar,                             // top
-		nil,                                 // bottom
-		nil,                                 // left
-		nil,                                 // right
-		container.NewScroll(sc.editorEntry), // center
+		container.NewVBox(toolbar, sc.buildChangeBanner(), sc.buildFindBar()), // top
+		sc.buildStatusBar(), // bottom
+		nil,                 // left
+		nil,                 // right
+		sc.mainSplit,        // center
 	)
 }
 
@@ -111,31 +254,46 @@ func (sc *GuiController) buildEditorUI() fyne.CanvasObject {
 func (sc *GuiController) createEditorToolbar() *fyne.Container {
 	// 保存按钮
 	saveBtn := widget.NewButton("保存", func() {
-		sc.saveFile()
+		sc.runCommand("file.save")
 	})
 
+	// 打开文件夹按钮
+	openFolderBtn := widget.NewButton("打开文件夹", func() {
+		sc.openFolder()
+	})
+
+	// 导出按钮
+	exportBtn := widget.NewButton("导出 HTML", func() {
+		sc.runCommand("file.export")
+	})
+
+	// 显示模式选择（Ctrl+E / Ctrl+P 切换）
+	sc.viewModeSelect = widget.NewRadioGroup(viewModeNames, func(selected string) {
+		for i, name := range viewModeNames {
+			if name == selected && viewMode(i) != sc.viewMode {
+				sc.setViewMode(viewMode(i))
+			}
+		}
+	})
+	sc.viewModeSelect.Horizontal = true
+	sc.viewModeSelect.Required = true
+	sc.viewModeSelect.Selected = sc.viewMode.String()
+
 	return container.NewHBox(
 		saveBtn,
+		openFolderBtn,
+		exportBtn,
+		widget.NewSeparator(),
+		sc.viewModeSelect,
 	)
 }
 
-// createNewFile 创建新文件
+// createNewFile 在新标签页中创建新文件
 func (sc *GuiController) createNewFile() {
-	sc.isEditing = true
-	sc.appState.SetCurrentFile("")
-	sc.appState.SetCurrentContent("# 新文档\n\n开始编写您的内容...")
-	sc.appState.SetOriginalContent("")
-
-	// 重新构建UI
-	sc.window.SetContent(sc.BuildUI(sc.window))
-
-	// 设置编辑器内容
-	if sc.editorEntry != nil {
-		sc.editorEntry.SetText(sc.appState.GetCurrentContent())
-	}
+	sc.openDocument(sc.newDocument("", "# 新文档\n\n开始编写您的内容...", ""))
 }
 
-// openFile 打开文件
+// openFile 显示打开文件对话框，在新标签页中打开选中的文件
 func (sc *GuiController) openFile() {
 	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
 		if err != nil || reader == nil {
@@ -144,93 +302,216 @@ func (sc *GuiController) openFile() {
 		defer reader.Close()
 
 		// 检查文件扩展名
-		filename := reader.URI().Name()
-		if !strings.HasSuffix(strings.ToLower(filename), ".md") &&
-			!strings.HasSuffix(strings.ToLower(filename), ".markdown") {
+		if !workspace.IsMarkdownFile(reader.URI().Name()) {
 			dialog.ShowInformation("错误", "请选择 Markdown 文件（.md 或 .markdown）", sc.window)
 			return
 		}
 
-		// 读取文件内容
-		content, err := sc.appState.LoadFile(reader.URI().Path())
-		if err != nil {
-			dialog.ShowError(err, sc.window)
-			return
-		}
+		sc.loadFile(reader.URI().Path())
+	}, sc.window)
+}
 
-		// 设置状态
-		sc.isEditing = true
-		sc.appState.SetCurrentFile(reader.URI().Path())
-		sc.appState.SetCurrentContent(content)
-		sc.appState.SetOriginalContent(content)
+// OpenPath 打开命令行指定的路径：文件夹作为工作区打开，文件在新标签页中打开
+func (sc *GuiController) OpenPath(path string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
 
-		// 重新构建UI
-		sc.window.SetContent(sc.BuildUI(sc.window))
+	if info.IsDir() {
+		sc.loadWorkspace(absPath)
+		return
+	}
+	if !workspace.IsMarkdownFile(absPath) {
+		dialog.ShowInformation("错误", "请选择 Markdown 文件（.md 或 .markdown）", sc.window)
+		return
+	}
+	sc.loadFile(absPath)
+}
 
-		// 设置编辑器内容
-		if sc.editorEntry != nil {
-			sc.editorEntry.SetText(content)
-		}
-	}, sc.window)
+// loadFile 在新标签页中打开指定路径的文件，文件已打开时切换到对应标签页
+func (sc *GuiController) loadFile(filePath string) {
+	if doc := sc.findDocument(filePath); doc != nil {
+		sc.selectDocument(doc)
+		return
+	}
+
+	// 读取文件内容
+	content, err := sc.appState.LoadFile(filePath)
+	if err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
+
+	sc.openDocument(sc.newDocument(filePath, content, content))
+	sc.addRecent(filePath, false)
 }
 
 // saveFile 保存文件
 func (sc *GuiController) saveFile() {
+	sc.saveFileThen(func() {
+		dialog.ShowInformation("保存成功", "文件已保存", sc.window)
+	})
+}
+
+// saveFileAs 把当前文档另存为新文件
+func (sc *GuiController) saveFileAs() {
+	sc.saveFileAsThen(func() {
+		dialog.ShowInformation("保存成功", "文件已保存", sc.window)
+	})
+}
+
+// saveFileThen 保存文件，保存成功后执行回调
+func (sc *GuiController) saveFileThen(onSaved func()) {
 	currentFile := sc.appState.GetCurrentFile()
+	if currentFile == "" {
+		sc.saveFileAsThen(onSaved)
+		return
+	}
+
+	// 文件在磁盘上被其他程序修改过时不直接覆盖，先让用户选择保留哪个版本
+	// 文件被删除时照常保存，重新创建文件
+	if changed, snapshot, err := sc.appState.CheckDiskChange(); err == nil && changed && snapshot.Exists {
+		sc.pendingSnapshot = snapshot
+		sc.showChangeBanner("文件已被其他程序修改，保存会覆盖这些修改。请先重新加载、比较或保留我的版本。", true)
+		return
+	}
+
+	// 直接保存
 	content := sc.appState.GetCurrentContent()
+	err := sc.appState.SaveFile(currentFile, content)
+	if err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
 
-	if currentFile == "" {
-		// 另存为
-		dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
-			if err != nil || writer == nil {
-				return
-			}
-			defer writer.Close()
+	sc.appState.SetOriginalContent(content)
+	sc.appState.UpdateDiskSnapshot()
+	sc.hideChangeBanner()
+	sc.updateWindowTitle()
+	sc.discardDraft(sc.current)
+	sc.linters.reset()
 
-			// 确保文件扩展名
-			filename := writer.URI().Name()
-			if !strings.HasSuffix(strings.ToLower(filename), ".md") &&
-				!strings.HasSuffix(strings.ToLower(filename), ".markdown") {
-				filename += ".md"
-			}
+	onSaved()
+}
 
-			// 写入文件
-			_, err = writer.Write([]byte(content))
-			if err != nil {
-				dialog.ShowError(err, sc.window)
-				return
-			}
+// saveFileAsThen 选择保存位置并保存文件，保存成功后执行回调
+func (sc *GuiController) saveFileAsThen(onSaved func()) {
+	content := sc.appState.GetCurrentContent()
 
-			// 更新状态
-			sc.appState.SetCurrentFile(writer.URI().Path())
-			sc.appState.SetOriginalContent(content)
+	dialog.ShowFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
 
-			dialog.ShowInformation("保存成功", "文件已保存", sc.window)
-		}, sc.window)
-	} else {
-		// 直接保存
-		err := sc.appState.SaveFile(currentFile, content)
-		if err != nil {
+		// 确保文件扩展名
+		writer.Close()
+		path := writer.URI().Path()
+		if !workspace.IsMarkdownFile(path) {
+			// 对话框已经创建了不带扩展名的空文件，改用 .md 文件保存时删除它
+			if info, err := os.Stat(path); err == nil && info.Size() == 0 {
+				os.Remove(path)
+			}
+			path += ".md"
+		}
+
+		// 写入文件（与直接保存使用相同的原子写入）
+		if err := sc.appState.SaveFile(path, content); err != nil {
 			dialog.ShowError(err, sc.window)
 			return
 		}
 
+		// 更新状态
+		sc.appState.SetCurrentFile(path)
+		sc.addRecent(path, false)
 		sc.appState.SetOriginalContent(content)
-		dialog.ShowInformation("保存成功", "文件已保存", sc.window)
+		sc.appState.UpdateDiskSnapshot()
+		sc.hideChangeBanner()
+		sc.updateWindowTitle()
+		sc.discardDraft(sc.current)
+		// 另存到其他目录后按新目录的配置重新检查
+		sc.linters.reset()
+		sc.runLint()
+
+		onSaved()
+	}, sc.window)
+}
+
+// OnWindowClose 窗口关闭时的处理，依次询问有未保存变更的文档，全部确认后调用 onClosed
+func (sc *GuiController) OnWindowClose(onClosed func()) {
+	sc.confirmAllUnsavedChanges(func() {
+		sc.saveSession()
+
+		// 正常退出时不保留草稿，草稿只用于崩溃后恢复
+		for _, doc := range sc.documents {
+			sc.discardDraft(doc)
+		}
+		onClosed()
+	})
+}
+
+// confirmUnsavedChanges 有未保存的变更时询问保存、不保存或取消，
+// 用户选择保存（且保存成功）或不保存后执行 onProceed
+func (sc *GuiController) confirmUnsavedChanges(onProceed func()) {
+	if !sc.hasDocument() || !sc.appState.HasUnsavedChanges() {
+		onProceed()
+		return
+	}
+
+	message := widget.NewLabel("“" + sc.documentName() + "”有未保存的更改，是否保存？")
+	confirm := dialog.NewCustomWithoutButtons("未保存的更改", message, sc.window)
+
+	saveBtn := widget.NewButton("保存", func() {
+		confirm.Hide()
+		sc.saveFileThen(onProceed)
+	})
+	saveBtn.Importance = widget.HighImportance
+	discardBtn := widget.NewButton("不保存", func() {
+		confirm.Hide()
+		onProceed()
+	})
+	cancelBtn := widget.NewButton("取消", func() {
+		confirm.Hide()
+	})
+
+	confirm.SetButtons([]fyne.CanvasObject{cancelBtn, discardBtn, saveBtn})
+	confirm.Show()
+}
+
+// documentName 获取当前文档的显示名称
+func (sc *GuiController) documentName() string {
+	if sc.current == nil {
+		return "未命名"
 	}
+	return sc.current.name()
 }
 
-// OnWindowClose 窗口关闭时的处理
-func (sc *GuiController) OnWindowClose() {
-	// 自动保存
-	if sc.isEditing && sc.appState.HasUnsavedChanges() {
-		currentFile := sc.appState.GetCurrentFile()
-		content := sc.appState.GetCurrentContent()
+// updateWindowTitle 更新窗口标题和当前标签页标题，有未保存的变更时在文件名后显示 *
+func (sc *GuiController) updateWindowTitle() {
+	if sc.window == nil {
+		return
+	}
 
-		if currentFile != "" {
-			// 如果有文件路径，直接保存
-			sc.appState.SaveFile(currentFile, content)
+	title := "MarkUp"
+	if sc.hasDocument() {
+		title = sc.documentName()
+		if sc.appState.HasUnsavedChanges() {
+			title += " *"
 		}
-		// 如果是新文件且没有路径，则不自动保存（避免弹出对话框）
+		title += " - MarkUp"
+	} else if sc.workspace != nil {
+		// 只打开了文件夹时显示工作区目录名
+		title = filepath.Base(sc.workspace.GetRoot()) + " - MarkUp"
+	}
+
+	if sc.window.Title() != title {
+		sc.window.SetTitle(title)
 	}
+	sc.updateTabTitle(sc.current)
 }
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// markdownCheatsheet Markdown 语法速查表
+const markdownCheatsheet = `## 标题
+
+    # 一级标题
+    ## 二级标题
+    ### 三级标题 {#自定义-id}
+
+## 文本格式
+
+    **粗体**  *斜体*  ~~删除线~~  ` + "`行内代码`" + `
+
+## 列表
+
+    - 无序列表
+    - 第二项
+      - 嵌套项
+
+    1. 有序列表
+    2. 第二项
+
+## 链接和图片
+
+    [链接文字](https://example.com)
+    ![图片说明](images/logo.png)
+
+## 引用
+
+    > 引用内容
+
+## 代码块
+
+    ` + "```go" + `
+    fmt.Println("hello")
+    ` + "```" + `
+
+## 表格
+
+    | 列一 | 列二 |
+    | ---- | ---- |
+    | 内容 | 内容 |
+
+## 分隔线
+
+    ---
+`
+
+// showCheatsheet 显示 Markdown 语法速查表
+func (sc *GuiController) showCheatsheet() {
+	content := widget.NewRichTextFromMarkdown(markdownCheatsheet)
+	content.Wrapping = fyne.TextWrapWord
+
+	cheatsheet := dialog.NewCustom("Markdown 速查表", "关闭", container.NewVScroll(content), sc.window)
+	cheatsheet.Resize(fyne.NewSize(560, 640))
+	cheatsheet.Show()
+}
+
+// showAbout 显示关于对话框
+func (sc *GuiController) showAbout() {
+	message := "MarkUp 编辑器\n基于 Fyne 的 Markdown 编辑器，支持实时预览、大纲、语法检查和 HTML 导出。"
+	if version := fyne.CurrentApp().Metadata().Version; version != "" {
+		message += "\n\n版本 " + version
+	}
+	dialog.ShowInformation("关于 MarkUp", message, sc.window)
+}
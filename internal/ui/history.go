@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"time"
+)
+
+// 编辑历史的参数
+const (
+	historyGroupInterval = time.Second // 间隔小于该值的连续输入合并为一次撤销
+	historyLimit         = 200         // 最多保留的撤销步数
+)
+
+// editSnapshot 编辑历史中的一个状态
+type editSnapshot struct {
+	text      string
+	cursorRow int
+	cursorCol int
+}
+
+// editHistory 编辑器的撤销/重做历史
+// widget.Entry 自带的历史会在 SetText 时清空，无法把整体替换作为一步撤销，因此由编辑器自己记录
+type editHistory struct {
+	undo       []editSnapshot
+	redo       []editSnapshot
+	last       editSnapshot // 最近一次记录的状态，即当前内容
+	lastChange time.Time    // 最近一次修改的时间
+	breakGroup bool         // 下一次修改是否必须单独作为一步
+	applying   bool         // 正在恢复历史状态，此时的修改不记录
+}
+
+// snapshot 获取编辑器的当前状态
+func (e *markdownEditor) snapshot() editSnapshot {
+	return editSnapshot{text: e.Text, cursorRow: e.CursorRow, cursorCol: e.CursorColumn}
+}
+
+// setContent 设置编辑器内容并清空编辑历史（打开或重新加载文档时使用）
+func (e *markdownEditor) setContent(text string) {
+	e.history.applying = true
+	e.SetText(text)
+	e.history.applying = false
+	e.history = editHistory{last: e.snapshot(), breakGroup: true}
+}
+
+// recordChange 记录一次内容修改，在编辑器的 OnChanged 中调用
+func (e *markdownEditor) recordChange() {
+	h := &e.history
+	if h.applying {
+		return
+	}
+
+	now := time.Now()
+	if h.breakGroup || now.Sub(h.lastChange) > historyGroupInterval {
+		h.undo = append(h.undo, h.last)
+		if len(h.undo) > historyLimit {
+			h.undo = h.undo[len(h.undo)-historyLimit:]
+		}
+	}
+	h.redo = nil
+	h.last = e.snapshot()
+	h.lastChange = now
+	h.breakGroup = false
+}
+
+// replaceText 把编辑器内容整体替换为 text，作为单独的一步撤销，并把光标移动到指定位置
+func (e *markdownEditor) replaceText(text string, cursorRow, cursorCol int) {
+	e.history.breakGroup = true
+	e.SetText(text)
+	e.CursorRow = cursorRow
+	e.CursorColumn = cursorCol
+	e.history.last = e.snapshot()
+	e.history.breakGroup = true
+	e.Refresh()
+}
+
+// undo 撤销上一步修改
+func (e *markdownEditor) undo() {
+	h := &e.history
+	if len(h.undo) == 0 {
+		return
+	}
+	previous := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, e.snapshot())
+	e.applySnapshot(previous)
+}
+
+// redo 重做上一步撤销的修改
+func (e *markdownEditor) redo() {
+	h := &e.history
+	if len(h.redo) == 0 {
+		return
+	}
+	next := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, e.snapshot())
+	e.applySnapshot(next)
+}
+
+// applySnapshot 恢复到历史状态
+func (e *markdownEditor) applySnapshot(state editSnapshot) {
+	e.history.applying = true
+	e.SetText(state.text)
+	e.history.applying = false
+
+	e.CursorRow = state.cursorRow
+	e.CursorColumn = state.cursorCol
+	e.Refresh()
+
+	e.history.last = state
+	e.history.breakGroup = true
+}
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// keyAliases 配置文件中可以使用的按键名称（不区分大小写），字母和数字直接使用
+var keyAliases = map[string]fyne.KeyName{
+	"tab":       fyne.KeyTab,
+	"space":     fyne.KeySpace,
+	"enter":     fyne.KeyReturn,
+	"return":    fyne.KeyReturn,
+	"esc":       fyne.KeyEscape,
+	"escape":    fyne.KeyEscape,
+	"backspace": fyne.KeyBackspace,
+	"delete":    fyne.KeyDelete,
+	"insert":    fyne.KeyInsert,
+	"home":      fyne.KeyHome,
+	"end":       fyne.KeyEnd,
+	"pageup":    fyne.KeyPageUp,
+	"pagedown":  fyne.KeyPageDown,
+	"up":        fyne.KeyUp,
+	"down":      fyne.KeyDown,
+	"left":      fyne.KeyLeft,
+	"right":     fyne.KeyRight,
+	"f1":        fyne.KeyF1,
+	"f2":        fyne.KeyF2,
+	"f3":        fyne.KeyF3,
+	"f4":        fyne.KeyF4,
+	"f5":        fyne.KeyF5,
+	"f6":        fyne.KeyF6,
+	"f7":        fyne.KeyF7,
+	"f8":        fyne.KeyF8,
+	"f9":        fyne.KeyF9,
+	"f10":       fyne.KeyF10,
+	"f11":       fyne.KeyF11,
+	"f12":       fyne.KeyF12,
+	"=":         fyne.KeyEqual,
+	"-":         fyne.KeyMinus,
+	"[":         fyne.KeyLeftBracket,
+	"]":         fyne.KeyRightBracket,
+	"\\":        fyne.KeyBackslash,
+	";":         fyne.KeySemicolon,
+	"'":         fyne.KeyApostrophe,
+	",":         fyne.KeyComma,
+	".":         fyne.KeyPeriod,
+	"/":         fyne.KeySlash,
+	"`":         fyne.KeyBackTick,
+}
+
+// modifierAliases 配置文件中可以使用的修饰键名称（不区分大小写），
+// mod 在 macOS 上为 Cmd，其他系统上为 Ctrl
+var modifierAliases = map[string]fyne.KeyModifier{
+	"mod":     fyne.KeyModifierShortcutDefault,
+	"ctrl":    fyne.KeyModifierControl,
+	"control": fyne.KeyModifierControl,
+	"shift":   fyne.KeyModifierShift,
+	"alt":     fyne.KeyModifierAlt,
+	"option":  fyne.KeyModifierAlt,
+	"cmd":     fyne.KeyModifierSuper,
+	"super":   fyne.KeyModifierSuper,
+	"meta":    fyne.KeyModifierSuper,
+}
+
+// parseShortcut 解析配置文件中的快捷键，如 "Mod+Shift+S"、"Ctrl+Tab"
+// 至少需要一个 Shift 以外的修饰键，否则按键会被当作普通输入
+func parseShortcut(text string) (fyne.Shortcut, error) {
+	parts := strings.Split(strings.TrimSpace(text), "+")
+	// "Ctrl++" 的最后一个按键是加号
+	if len(parts) > 2 && parts[len(parts)-1] == "" && parts[len(parts)-2] == "" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+
+	var modifier fyne.KeyModifier
+	for _, part := range parts[:len(parts)-1] {
+		mod, ok := modifierAliases[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return nil, fmt.Errorf("未知的修饰键 %q", part)
+		}
+		modifier |= mod
+	}
+	if modifier&^fyne.KeyModifierShift == 0 {
+		return nil, fmt.Errorf("需要 Ctrl、Alt 或 Cmd 等修饰键")
+	}
+
+	key, err := parseKeyName(parts[len(parts)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	// Fyne 会把 Ctrl+Z / Ctrl+Y 识别为撤销和重做，需要使用相同的快捷键类型才能匹配
+	if modifier == fyne.KeyModifierShortcutDefault {
+		switch key {
+		case fyne.KeyZ:
+			return &fyne.ShortcutUndo{}, nil
+		case fyne.KeyY:
+			return &fyne.ShortcutRedo{}, nil
+		}
+	}
+	return &desktop.CustomShortcut{KeyName: key, Modifier: modifier}, nil
+}
+
+// parseKeyName 解析按键名称
+func parseKeyName(text string) (fyne.KeyName, error) {
+	text = strings.TrimSpace(text)
+	if text == "+" {
+		return fyne.KeyPlus, nil
+	}
+	if key, ok := keyAliases[strings.ToLower(text)]; ok {
+		return key, nil
+	}
+	if len(text) == 1 {
+		if c := strings.ToUpper(text)[0]; (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return fyne.KeyName(string(c)), nil
+		}
+	}
+	return "", fmt.Errorf("未知的按键 %q", text)
+}
+
+// formatShortcut 把快捷键格式化为界面中显示的文本，如 "Ctrl+Shift+S"
+func formatShortcut(shortcut fyne.Shortcut) string {
+	keyboard, ok := shortcut.(fyne.KeyboardShortcut)
+	if !ok {
+		return shortcut.ShortcutName()
+	}
+
+	modifier := keyboard.Mod()
+	var parts []string
+	if modifier&fyne.KeyModifierControl != 0 {
+		parts = append(parts, "Ctrl")
+	}
+	if modifier&fyne.KeyModifierAlt != 0 {
+		parts = append(parts, "Alt")
+	}
+	if modifier&fyne.KeyModifierShift != 0 {
+		parts = append(parts, "Shift")
+	}
+	if modifier&fyne.KeyModifierSuper != 0 {
+		if runtime.GOOS == "darwin" {
+			parts = append(parts, "Cmd")
+		} else {
+			parts = append(parts, "Super")
+		}
+	}
+	return strings.Join(append(parts, string(keyboard.Key())), "+")
+}
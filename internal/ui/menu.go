@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+)
+
+// refreshMainMenu 重新构建主菜单，使菜单项的可用状态和勾选状态与当前界面一致
+func (sc *GuiController) refreshMainMenu() {
+	if sc.window == nil {
+		return
+	}
+	sc.window.SetMainMenu(fyne.NewMainMenu(
+		sc.buildFileMenu(),
+		sc.buildEditMenu(),
+		sc.buildViewMenu(),
+		sc.buildHelpMenu(),
+	))
+}
+
+// commandItem 创建执行指定命令的菜单项，显示命令当前绑定的快捷键
+// 当前状态下不可用的命令（如没有打开文档时的保存）禁用
+func (sc *GuiController) commandItem(id string) *fyne.MenuItem {
+	cmd := sc.commands.lookup(id)
+	item := fyne.NewMenuItem(cmd.title, func() {
+		sc.executeCommand(cmd)
+	})
+	item.Shortcut = cmd.shortcut
+	item.Disabled = !sc.commandEnabled(cmd)
+	return item
+}
+
+// checkedItem 创建带勾选状态的命令菜单项
+func (sc *GuiController) checkedItem(id string, checked bool) *fyne.MenuItem {
+	item := sc.commandItem(id)
+	item.Checked = checked
+	return item
+}
+
+// buildFileMenu 构建文件菜单
+func (sc *GuiController) buildFileMenu() *fyne.Menu {
+	recentItem := fyne.NewMenuItem("最近打开", nil)
+	recentItem.ChildMenu = sc.buildRecentMenu()
+
+	quitItem := sc.commandItem("file.quit")
+	quitItem.IsQuit = true
+
+	return fyne.NewMenu("文件",
+		sc.commandItem("file.new"),
+		sc.commandItem("file.open"),
+		sc.commandItem("file.quickOpen"),
+		sc.commandItem("file.openFolder"),
+		recentItem,
+		fyne.NewMenuItemSeparator(),
+		sc.commandItem("file.save"),
+		sc.commandItem("file.saveAs"),
+		sc.commandItem("file.export"),
+		fyne.NewMenuItemSeparator(),
+		sc.commandItem("file.close"),
+		quitItem,
+	)
+}
+
+// buildEditMenu 构建编辑菜单
+func (sc *GuiController) buildEditMenu() *fyne.Menu {
+	return fyne.NewMenu("编辑",
+		sc.commandItem("edit.undo"),
+		sc.commandItem("edit.redo"),
+		fyne.NewMenuItemSeparator(),
+		sc.commandItem("format.bold"),
+		sc.commandItem("format.italic"),
+		sc.commandItem("format.insertTable"),
+		fyne.NewMenuItemSeparator(),
+		sc.commandItem("edit.find"),
+		sc.commandItem("edit.replace"),
+		sc.commandItem("edit.findNext"),
+		sc.commandItem("edit.findPrevious"),
+		sc.commandItem("edit.findInFiles"),
+		sc.commandItem("edit.replaceInFiles"),
+		sc.commandItem("edit.revertReplace"),
+	)
+}
+
+// buildViewMenu 构建视图菜单：命令面板、显示模式、配色和缩放
+func (sc *GuiController) buildViewMenu() *fyne.Menu {
+	return fyne.NewMenu("视图",
+		sc.commandItem("view.commandPalette"),
+		fyne.NewMenuItemSeparator(),
+		sc.checkedItem("view.split", sc.viewMode == viewModeSplit),
+		sc.checkedItem("view.edit", sc.viewMode == viewModeEdit),
+		sc.checkedItem("view.preview", sc.viewMode == viewModePreview),
+		sc.commandItem("view.problems"),
+		sc.commandItem("view.runLint"),
+		sc.commandItem("view.refreshFiles"),
+		fyne.NewMenuItemSeparator(),
+		sc.checkedItem("view.lightTheme", !sc.darkTheme),
+		sc.checkedItem("view.darkTheme", sc.darkTheme),
+		fyne.NewMenuItemSeparator(),
+		sc.commandItem("view.zoomIn"),
+		sc.commandItem("view.zoomOut"),
+		sc.commandItem("view.zoomReset"),
+	)
+}
+
+// buildHelpMenu 构建帮助菜单
+func (sc *GuiController) buildHelpMenu() *fyne.Menu {
+	return fyne.NewMenu("帮助",
+		sc.commandItem("help.cheatsheet"),
+		fyne.NewMenuItemSeparator(),
+		sc.commandItem("help.about"),
+	)
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// buildOutlinePanel 构建中间的大纲面板
+func (sc *GuiController) buildOutlinePanel() fyne.CanvasObject {
+	sc.outlineList = widget.NewList(
+		// 条目数量
+		func() int {
+			return len(sc.appState.GetOutline())
+		},
+		// 创建条目
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		// 更新条目，按标题级别缩进
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			outline := sc.appState.GetOutline()
+			if id >= len(outline) {
+				return
+			}
+			entry := outline[id]
+			label := obj.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{Bold: entry.Level == 1}
+			label.SetText(strings.Repeat("    ", entry.Level-1) + entry.Title)
+		},
+	)
+
+	// 点击条目时跳转到对应行
+	sc.outlineList.OnSelected = func(id widget.ListItemID) {
+		outline := sc.appState.GetOutline()
+		if id < len(outline) {
+			sc.goToLine(outline[id].Line)
+		}
+		sc.outlineList.UnselectAll()
+	}
+
+	title := widget.NewLabel("目录")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	return container.NewBorder(title, nil, nil, nil, sc.outlineList)
+}
+
+// updateOutline 根据当前内容重新提取大纲并刷新大纲面板
+func (sc *GuiController) updateOutline() {
+	outline := sc.mdRenderer.ExtractOutline(sc.appState.GetCurrentContent())
+	sc.appState.SetOutline(outline)
+
+	if sc.outlineList != nil {
+		sc.outlineList.Refresh()
+	}
+}
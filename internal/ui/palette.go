@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"markup/internal/search"
+)
+
+// showCommandPalette 显示命令面板：列出注册表中的所有命令及其快捷键，可以模糊搜索命令名称或ID
+func (sc *GuiController) showCommandPalette() {
+	// 只列出当前状态下可用的命令
+	var commands []*command
+	for _, cmd := range sc.commands.commands {
+		if cmd.id == "view.commandPalette" || !sc.commandEnabled(cmd) {
+			continue
+		}
+		commands = append(commands, cmd)
+	}
+
+	sc.showPicker("输入命令名称", func(query string) []pickerItem {
+		type scored struct {
+			cmd   *command
+			score int
+		}
+		var matches []scored
+		for _, cmd := range commands {
+			titleScore, titleOK := search.FuzzyScore(query, cmd.title)
+			idScore, idOK := search.FuzzyScore(query, cmd.id)
+			if !titleOK && !idOK {
+				continue
+			}
+			score := titleScore
+			if idOK && (!titleOK || idScore > titleScore) {
+				score = idScore
+			}
+			matches = append(matches, scored{cmd, score})
+		}
+		// 没有输入时保持注册顺序
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+
+		items := make([]pickerItem, len(matches))
+		for i, match := range matches {
+			cmd := match.cmd
+			detail := ""
+			if cmd.shortcut != nil {
+				detail = formatShortcut(cmd.shortcut)
+			}
+			items[i] = pickerItem{
+				title:  strings.TrimSuffix(cmd.title, "..."),
+				detail: detail,
+				run: func() {
+					sc.executeCommand(cmd)
+				},
+			}
+		}
+		return items
+	})
+}
@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxPickerItems 选择框中最多显示的条目数
+const maxPickerItems = 200
+
+// pickerItem 选择框中的一个条目
+type pickerItem struct {
+	title  string // 标题
+	detail string // 显示在右侧的说明（如文件所在目录、快捷键）
+	run    func() // 选中后执行的操作
+}
+
+// picker 显示在窗口上方的选择框，输入内容时过滤条目，回车执行选中的条目
+// 快速打开文件和命令面板都使用它
+type picker struct {
+	popUp    *widget.PopUp
+	input    *pickerEntry
+	list     *widget.List
+	items    []pickerItem
+	selected int
+
+	filter func(query string) []pickerItem // 按输入内容获取条目
+	moving bool                            // 正在用方向键移动选中项，此时不执行条目
+}
+
+// pickerEntry 选择框的输入框，处理方向键和 Esc
+type pickerEntry struct {
+	widget.Entry
+	onMove   func(step int)
+	onEscape func()
+}
+
+// newPickerEntry 创建选择框的输入框
+func newPickerEntry(placeHolder string) *pickerEntry {
+	e := &pickerEntry{}
+	e.SetPlaceHolder(placeHolder)
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedKey 上下方向键移动选中项，Esc 关闭选择框，其余按键交给 widget.Entry 处理
+func (e *pickerEntry) TypedKey(key *fyne.KeyEvent) {
+	switch key.Name {
+	case fyne.KeyUp:
+		e.onMove(-1)
+	case fyne.KeyDown:
+		e.onMove(1)
+	case fyne.KeyPageUp:
+		e.onMove(-10)
+	case fyne.KeyPageDown:
+		e.onMove(10)
+	case fyne.KeyEscape:
+		e.onEscape()
+	default:
+		e.Entry.TypedKey(key)
+	}
+}
+
+// showPicker 显示选择框，filter 根据输入内容返回按优先级排列的条目
+func (sc *GuiController) showPicker(placeHolder string, filter func(query string) []pickerItem) {
+	p := &picker{filter: filter}
+
+	p.input = newPickerEntry(placeHolder)
+	p.input.onMove = p.move
+	p.input.onEscape = func() {
+		sc.closePicker(p)
+	}
+	p.input.OnChanged = func(query string) {
+		p.update(query)
+	}
+	p.input.OnSubmitted = func(string) {
+		sc.runPickerItem(p, p.selected)
+	}
+
+	p.list = widget.NewList(
+		func() int {
+			return len(p.items)
+		},
+		func() fyne.CanvasObject {
+			title := widget.NewLabel("")
+			title.Truncation = fyne.TextTruncateEllipsis
+			detail := widget.NewLabel("")
+			detail.Importance = widget.LowImportance
+			return container.NewBorder(nil, nil, nil, detail, title)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(p.items) {
+				return
+			}
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(p.items[id].title)
+			row.Objects[1].(*widget.Label).SetText(p.items[id].detail)
+		},
+	)
+	// 用鼠标点击条目时直接执行
+	p.list.OnSelected = func(id widget.ListItemID) {
+		if !p.moving {
+			sc.runPickerItem(p, id)
+		}
+	}
+
+	content := container.NewBorder(p.input, nil, nil, nil, p.list)
+	p.popUp = widget.NewModalPopUp(content, sc.window.Canvas())
+
+	// 宽度为窗口的一半左右，显示在窗口上方
+	size := sc.window.Canvas().Size()
+	width := max(size.Width*0.5, min(size.Width-40, 480))
+	p.popUp.Resize(fyne.NewSize(width, size.Height*0.6))
+	p.popUp.ShowAtPosition(fyne.NewPos((size.Width-width)/2, size.Height*0.08))
+
+	p.update("")
+	sc.window.Canvas().Focus(p.input)
+}
+
+// update 按输入内容刷新条目并选中第一项
+func (p *picker) update(query string) {
+	p.items = p.filter(query)
+	if len(p.items) > maxPickerItems {
+		p.items = p.items[:maxPickerItems]
+	}
+	p.list.Refresh()
+	p.selected = -1
+	p.move(1)
+}
+
+// move 移动选中项，到达两端时停止
+func (p *picker) move(step int) {
+	if len(p.items) == 0 {
+		p.selected = -1
+		p.list.UnselectAll()
+		return
+	}
+	p.selected = min(max(p.selected+step, 0), len(p.items)-1)
+
+	p.moving = true
+	p.list.Select(p.selected)
+	p.moving = false
+}
+
+// runPickerItem 关闭选择框并执行指定条目
+func (sc *GuiController) runPickerItem(p *picker, id int) {
+	if id < 0 || id >= len(p.items) {
+		return
+	}
+	sc.closePicker(p)
+	p.items[id].run()
+}
+
+// closePicker 关闭选择框，焦点回到编辑器
+func (sc *GuiController) closePicker(p *picker) {
+	p.popUp.Hide()
+	if sc.hasDocument() && sc.viewMode != viewModePreview {
+		sc.window.Canvas().Focus(sc.editorEntry)
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// buildPreviewPanel 构建右侧的预览面板
+func (sc *GuiController) buildPreviewPanel() fyne.CanvasObject {
+	sc.previewText = widget.NewRichTextFromMarkdown("")
+	sc.previewText.Wrapping = fyne.TextWrapWord
+	sc.previewScroll = container.NewVScroll(sc.previewText)
+
+	return sc.previewScroll
+}
+
+// updatePreview 根据当前内容重新渲染预览
+func (sc *GuiController) updatePreview() {
+	if sc.previewText == nil {
+		return
+	}
+
+	content := sc.mdRenderer.RenderToRichText(sc.appState.GetCurrentContent())
+	sc.previewText.ParseMarkdown(content)
+	sc.syncPreviewScroll()
+}
+
+// editorTopLine 获取编辑器可视区域顶部的行号（从0开始）
+func (sc *GuiController) editorTopLine() int {
+	if sc.editorScroll == nil {
+		return 0
+	}
+	return int(sc.editorScroll.Offset.Y / sc.editorLineHeight())
+}
+
+// syncPreviewScroll 按编辑器顶部可见行在全文中的比例同步预览的滚动位置
+func (sc *GuiController) syncPreviewScroll() {
+	if sc.previewScroll == nil || !sc.hasDocument() {
+		return
+	}
+
+	lineCount := strings.Count(sc.editorEntry.Text, "\n") + 1
+	ratio := float32(sc.editorTopLine()) / float32(lineCount)
+
+	maxOffset := sc.previewText.MinSize().Height - sc.previewScroll.Size().Height
+	if maxOffset <= 0 {
+		sc.previewScroll.ScrollToTop()
+		return
+	}
+
+	offset := ratio * sc.previewText.MinSize().Height
+	if offset > maxOffset {
+		offset = maxOffset
+	}
+	sc.previewScroll.ScrollToOffset(fyne.NewPos(0, offset))
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"path/filepath"
+	"sort"
+
+	"markup/internal/search"
+)
+
+// recentFileBonus 最近打开的文件在快速打开中的加分，越近打开的文件加分越多
+const recentFileBonus = 8
+
+// quickOpenCandidate 快速打开中可以选择的文件
+type quickOpenCandidate struct {
+	path  string // 文件路径
+	label string // 用于匹配和显示的路径：工作区内的文件为相对路径
+	bonus int    // 最近打开或已打开的文件的加分
+}
+
+// showQuickOpen 显示快速打开：模糊匹配工作区中的文件、最近打开的文件和已打开的文档
+func (sc *GuiController) showQuickOpen() {
+	candidates := sc.quickOpenCandidates()
+
+	sc.showPicker("输入文件名快速打开", func(query string) []pickerItem {
+		type scored struct {
+			candidate quickOpenCandidate
+			score     int
+		}
+		var matches []scored
+		for _, candidate := range candidates {
+			if score, ok := search.FuzzyScore(query, candidate.label); ok {
+				matches = append(matches, scored{candidate, score + candidate.bonus})
+			}
+		}
+		// 没有输入时按最近打开排列，否则按得分排列
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+
+		items := make([]pickerItem, len(matches))
+		for i, match := range matches {
+			path := match.candidate.path
+			dir := filepath.Dir(match.candidate.label)
+			if dir == "." {
+				dir = ""
+			}
+			items[i] = pickerItem{
+				title:  filepath.Base(path),
+				detail: dir,
+				run: func() {
+					sc.loadFile(path)
+				},
+			}
+		}
+		return items
+	})
+}
+
+// quickOpenCandidates 收集可以快速打开的文件：最近打开的文件在前并且有加分，其后是已打开的文档和工作区中的文件
+func (sc *GuiController) quickOpenCandidates() []quickOpenCandidate {
+	var candidates []quickOpenCandidate
+	seen := make(map[string]bool)
+	add := func(path string, bonus int) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		candidates = append(candidates, quickOpenCandidate{path: path, label: sc.quickOpenLabel(path), bonus: bonus})
+	}
+
+	if sc.recent != nil {
+		entries := sc.recent.Entries()
+		// 固定的条目排在前面，这里只按打开时间排列
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].OpenedAt.After(entries[j].OpenedAt)
+		})
+		var files []string
+		for _, entry := range entries {
+			if !entry.IsDir {
+				files = append(files, entry.Path)
+			}
+		}
+		for i, path := range files {
+			add(path, (len(files)-i)*recentFileBonus)
+		}
+	}
+	for _, doc := range sc.documents {
+		add(doc.state.GetCurrentFile(), recentFileBonus)
+	}
+	if sc.workspace != nil {
+		for _, path := range sc.workspace.Files() {
+			add(path, 0)
+		}
+	}
+	return candidates
+}
+
+// quickOpenLabel 获取文件在快速打开中显示的路径，工作区内的文件显示相对路径
+func (sc *GuiController) quickOpenLabel(path string) string {
+	if sc.workspace != nil {
+		if rel, err := filepath.Rel(sc.workspace.GetRoot(), path); err == nil && filepath.IsLocal(rel) {
+			return rel
+		}
+	}
+	return path
+}
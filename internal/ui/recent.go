@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/core"
+)
+
+// addRecent 记录打开了文件或文件夹并刷新最近打开列表
+func (sc *GuiController) addRecent(path string, isDir bool) {
+	if sc.recent == nil {
+		return
+	}
+	// 最近打开列表只是便利功能，保存失败时不打扰用户
+	sc.recent.Add(path, isDir)
+	sc.refreshRecent()
+}
+
+// refreshRecent 刷新文件菜单和启动界面中的最近打开列表
+func (sc *GuiController) refreshRecent() {
+	sc.refreshMainMenu()
+	if !sc.isEditing {
+		sc.window.SetContent(sc.buildStartupUI())
+	}
+}
+
+// openRecent 打开最近打开列表中的条目，路径已不存在时将其移除
+func (sc *GuiController) openRecent(entry core.RecentEntry) {
+	if _, err := os.Stat(entry.Path); err != nil {
+		sc.recent.Remove(entry.Path)
+		sc.refreshRecent()
+		dialog.ShowInformation("无法打开", "“"+entry.Path+"”已不存在，已从最近打开列表中移除", sc.window)
+		return
+	}
+
+	if entry.IsDir {
+		sc.loadWorkspace(entry.Path)
+	} else {
+		sc.loadFile(entry.Path)
+	}
+}
+
+// clearRecent 清除最近打开列表中未固定的条目
+func (sc *GuiController) clearRecent() {
+	sc.recent.Clear()
+	sc.refreshRecent()
+}
+
+// buildRecentPanel 构建启动界面中的最近打开列表，没有记录时返回 nil
+func (sc *GuiController) buildRecentPanel() fyne.CanvasObject {
+	if sc.recent == nil {
+		return nil
+	}
+	sc.recent.Prune()
+	entries := sc.recent.Entries()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := container.NewVBox()
+	for _, entry := range entries {
+		rows.Add(sc.buildRecentRow(entry))
+	}
+
+	scroll := container.NewVScroll(rows)
+	scroll.SetMinSize(fyne.NewSize(560, 240))
+
+	title := widget.NewLabel("最近打开")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	clearBtn := widget.NewButton("清除最近记录", sc.clearRecent)
+	clearBtn.Importance = widget.LowImportance
+
+	return container.NewBorder(container.NewBorder(nil, nil, nil, clearBtn, title), nil, nil, nil, scroll)
+}
+
+// buildRecentRow 构建最近打开列表中的一行：图标、名称、所在目录、固定和移除按钮
+func (sc *GuiController) buildRecentRow(entry core.RecentEntry) fyne.CanvasObject {
+	icon := theme.DocumentIcon()
+	if entry.IsDir {
+		icon = theme.FolderIcon()
+	}
+
+	openBtn := widget.NewButton(filepath.Base(entry.Path), func() {
+		sc.openRecent(entry)
+	})
+	openBtn.Alignment = widget.ButtonAlignLeading
+	openBtn.Importance = widget.LowImportance
+
+	dirLabel := widget.NewLabel(filepath.Dir(entry.Path))
+	dirLabel.Importance = widget.LowImportance
+	dirLabel.Truncation = fyne.TextTruncateEllipsis
+
+	pinText := "固定"
+	if entry.Pinned {
+		pinText = "取消固定"
+	}
+	pinBtn := widget.NewButton(pinText, func() {
+		sc.recent.SetPinned(entry.Path, !entry.Pinned)
+		sc.refreshRecent()
+	})
+	pinBtn.Importance = widget.LowImportance
+
+	removeBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), func() {
+		sc.recent.Remove(entry.Path)
+		sc.refreshRecent()
+	})
+	removeBtn.Importance = widget.LowImportance
+
+	return container.NewBorder(nil, nil,
+		container.NewHBox(widget.NewIcon(icon), openBtn),
+		container.NewHBox(pinBtn, removeBtn),
+		dirLabel,
+	)
+}
+
+// buildRecentMenu 构建文件菜单中的最近打开子菜单
+func (sc *GuiController) buildRecentMenu() *fyne.Menu {
+	var items []*fyne.MenuItem
+	if sc.recent != nil {
+		for _, entry := range sc.recent.Entries() {
+			entry := entry
+			label := filepath.Base(entry.Path) + "    " + filepath.Dir(entry.Path)
+			if entry.Pinned {
+				label = "★ " + label
+			}
+			items = append(items, fyne.NewMenuItem(label, func() {
+				sc.openRecent(entry)
+			}))
+		}
+	}
+
+	if len(items) == 0 {
+		empty := fyne.NewMenuItem("（无）", nil)
+		empty.Disabled = true
+		return fyne.NewMenu("", empty)
+	}
+
+	items = append(items, fyne.NewMenuItemSeparator(), sc.commandItem("file.clearRecent"))
+	return fyne.NewMenu("", items...)
+}
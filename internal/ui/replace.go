@@ -0,0 +1,437 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/core"
+	"markup/internal/search"
+)
+
+// replaceFile 替换预览中的一个文件
+type replaceFile struct {
+	result *search.FileResult
+	ticked []bool // 每处匹配是否替换
+}
+
+// selectedMatches 获取勾选的匹配
+func (f *replaceFile) selectedMatches() []search.Match {
+	var matches []search.Match
+	for i, match := range f.result.Matches {
+		if f.ticked[i] {
+			matches = append(matches, match.Match)
+		}
+	}
+	return matches
+}
+
+// replacePreviewItem 替换预览列表中的一行，match 为 -1 时表示文件本身
+type replacePreviewItem struct {
+	file  int
+	match int
+}
+
+// replacePreview 工作区替换的预览
+type replacePreview struct {
+	query       string
+	matcher     *search.Matcher
+	replacement string
+	files       []*replaceFile
+	items       []replacePreviewItem
+	current     int // 正在显示差异的文件
+}
+
+// newContent 获取文件替换勾选的匹配后的内容
+func (p *replacePreview) newContent(f *replaceFile) string {
+	return p.matcher.ReplaceAll(f.result.Content, f.selectedMatches(), p.replacement)
+}
+
+// showWorkspaceReplace 切换到左侧栏的搜索面板并聚焦替换内容
+func (sc *GuiController) showWorkspaceReplace() {
+	sc.showWorkspaceSearch()
+	if sc.workspace != nil && sc.search != nil {
+		sc.window.Canvas().Focus(sc.search.replacement)
+	}
+}
+
+// previewWorkspaceReplace 显示替换预览：可以逐处取消勾选，并查看每个文件的差异
+func (sc *GuiController) previewWorkspaceReplace() {
+	p := sc.search
+	if p == nil {
+		return
+	}
+	if p.cancel != nil {
+		p.status.SetText("请等待搜索完成后再替换")
+		return
+	}
+	if p.matcher == nil || len(p.results) == 0 {
+		p.status.SetText("没有可以替换的结果，请先搜索")
+		return
+	}
+
+	preview := &replacePreview{query: p.queryText, matcher: p.matcher, replacement: p.replacement.Text}
+	for i, result := range p.results {
+		file := &replaceFile{result: result, ticked: make([]bool, len(result.Matches))}
+		for j := range file.ticked {
+			file.ticked[j] = true
+		}
+		preview.files = append(preview.files, file)
+		preview.items = append(preview.items, replacePreviewItem{file: i, match: -1})
+		for j := range result.Matches {
+			preview.items = append(preview.items, replacePreviewItem{file: i, match: j})
+		}
+	}
+
+	// 右侧显示当前文件的差异
+	diffView := widget.NewMultiLineEntry()
+	diffView.TextStyle = fyne.TextStyle{Monospace: true}
+	diffView.Wrapping = fyne.TextWrapOff
+	diffView.Disable()
+	showDiff := func() {
+		file := preview.files[preview.current]
+		rel := file.result.RelPath
+		diff := core.UnifiedDiff(rel, rel, file.result.Content, preview.newContent(file), 2)
+		if diff == "" {
+			diff = "没有勾选要替换的内容"
+		}
+		diffView.SetText(diff)
+	}
+
+	summary := widget.NewLabel("")
+	updateSummary := func() {
+		files, matches := 0, 0
+		for _, file := range preview.files {
+			if n := len(file.selectedMatches()); n > 0 {
+				files++
+				matches += n
+			}
+		}
+		summary.SetText(fmt.Sprintf("将“%s”替换为“%s”：%d 个文件中的 %d 处", preview.query, preview.replacement, files, matches))
+	}
+
+	// 左侧按文件列出每处匹配，勾选文件时勾选其中的所有匹配
+	var list *widget.List
+	list = widget.NewList(
+		func() int {
+			return len(preview.items)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewCheck("", nil)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			item := preview.items[id]
+			file := preview.files[item.file]
+			check := obj.(*widget.Check)
+			check.OnChanged = nil
+
+			if item.match < 0 {
+				check.Text = fmt.Sprintf("%s (%d/%d)", file.result.RelPath, len(file.selectedMatches()), len(file.ticked))
+				check.SetChecked(len(file.selectedMatches()) == len(file.ticked))
+				check.Refresh()
+				check.OnChanged = func(checked bool) {
+					for i := range file.ticked {
+						file.ticked[i] = checked
+					}
+					preview.current = item.file
+					list.Refresh()
+					showDiff()
+					updateSummary()
+				}
+				return
+			}
+
+			match := &file.result.Matches[item.match]
+			replacement := preview.matcher.Expand(file.result.Content, match.Match, preview.replacement)
+			check.Text = fmt.Sprintf("    第%d行：%s → %s", match.Line, match.Text(), replacement)
+			check.SetChecked(file.ticked[item.match])
+			check.Refresh()
+			check.OnChanged = func(checked bool) {
+				file.ticked[item.match] = checked
+				preview.current = item.file
+				list.Refresh()
+				showDiff()
+				updateSummary()
+			}
+		},
+	)
+	// 点击某一行时显示所在文件的差异
+	list.OnSelected = func(id widget.ListItemID) {
+		preview.current = preview.items[id].file
+		showDiff()
+	}
+
+	showDiff()
+	updateSummary()
+
+	split := container.NewHSplit(list, diffView)
+	split.Offset = 0.4
+	content := container.NewBorder(summary, nil, nil, nil, split)
+
+	d := dialog.NewCustomConfirm("在工作区中替换", "替换", "取消", content, func(confirmed bool) {
+		if confirmed {
+			sc.applyWorkspaceReplace(preview)
+		}
+	}, sc.window)
+	size := sc.window.Canvas().Size()
+	d.Resize(fyne.NewSize(size.Width*0.85, size.Height*0.85))
+	d.Show()
+}
+
+// applyWorkspaceReplace 把预览中勾选的替换写入文件，并保存替换记录以便撤销
+// 替换记录在写入文件之前保存，写入过程中崩溃也可以撤销已写入的文件；
+// 已打开的文档在写入后同时在编辑器中替换（可以在该文档中撤销）
+func (sc *GuiController) applyWorkspaceReplace(preview *replacePreview) {
+	var edits []core.FileEdit
+	var problems []string
+	opened := make(map[string]*document)
+	counts := make(map[string]int)
+
+	for _, file := range preview.files {
+		matches := file.selectedMatches()
+		if len(matches) == 0 {
+			continue
+		}
+		path := file.result.Path
+		newContent := preview.newContent(file)
+		counts[path] = len(matches)
+
+		// 有未保存修改的文档不替换，否则会把这些修改一起写入磁盘；
+		// 搜索之后在编辑器中修改过的文档也不替换，避免按过期的位置替换
+		if doc := sc.findDocument(path); doc != nil {
+			if doc.state.HasUnsavedChanges() {
+				problems = append(problems, fmt.Sprintf("%s: 有未保存的修改，请先保存后再替换", file.result.RelPath))
+				continue
+			}
+			if doc.editor.Text != file.result.Content {
+				problems = append(problems, fmt.Sprintf("%s: 搜索之后内容已被修改", file.result.RelPath))
+				continue
+			}
+			opened[path] = doc
+		}
+		// 写入前会确认磁盘上的内容仍与搜索时一致
+		edits = append(edits, core.FileEdit{Path: path, Before: file.result.Content, After: newContent})
+	}
+	if len(edits) == 0 {
+		dialog.ShowInformation("替换完成", "没有替换任何内容。\n\n"+strings.Join(problems, "\n"), sc.window)
+		return
+	}
+
+	// 先记录计划写入的所有文件，保存失败时不修改任何文件
+	journal := &core.ReplaceJournal{
+		ID:          core.NewDraftID(),
+		Description: fmt.Sprintf("将“%s”替换为“%s”", preview.query, preview.replacement),
+		CreatedAt:   time.Now(),
+		Files:       edits,
+	}
+	if err := sc.saveJournal(journal); err != nil {
+		dialog.ShowError(fmt.Errorf("无法保存替换记录，已取消替换：%w", err), sc.window)
+		return
+	}
+
+	applied, err := core.ApplyEdits(edits, sc.settings.KeepBackup)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	// 记录中只保留实际写入的文件
+	if len(applied) == 0 {
+		sc.journals.Remove(journal.ID)
+	} else if len(applied) < len(edits) {
+		journal.Files = applied
+		if err := sc.journals.Save(journal); err != nil {
+			problems = append(problems, "无法更新替换记录："+err.Error())
+		}
+	}
+	sc.updateRevertButton()
+
+	replaced := 0
+	for _, edit := range applied {
+		replaced += counts[edit.Path]
+		if doc := opened[edit.Path]; doc != nil {
+			row := min(doc.editor.CursorRow, strings.Count(edit.After, "\n"))
+			doc.editor.replaceText(edit.After, row, 0)
+			doc.state.SetOriginalContent(edit.After)
+			doc.state.UpdateDiskSnapshot()
+			sc.updateTabTitle(doc)
+		}
+	}
+	sc.updateWindowTitle()
+
+	message := fmt.Sprintf("已在 %d 个文件中替换 %d 处。", len(applied), replaced)
+	if len(problems) > 0 {
+		message += "\n\n以下问题需要注意：\n" + strings.Join(problems, "\n")
+	}
+	dialog.ShowInformation("替换完成", message, sc.window)
+
+	// 重新搜索以显示替换后的结果
+	sc.startWorkspaceSearch()
+}
+
+// saveJournal 保存替换记录，并启用撤销按钮
+func (sc *GuiController) saveJournal(journal *core.ReplaceJournal) error {
+	if sc.journals == nil {
+		return fmt.Errorf("无法确定用户配置目录")
+	}
+	if err := sc.journals.Save(journal); err != nil {
+		return err
+	}
+	sc.updateRevertButton()
+	return nil
+}
+
+// revertWorkspaceReplace 显示工作区替换的记录，可以选择记录中的文件逐个恢复，也可以恢复整个替换
+// 替换后又被修改过的文件保持不变
+func (sc *GuiController) revertWorkspaceReplace() {
+	if sc.journals == nil {
+		return
+	}
+	journals, err := sc.journals.List()
+	if err != nil {
+		dialog.ShowError(err, sc.window)
+		return
+	}
+	if len(journals) == 0 {
+		dialog.ShowInformation("撤销替换", "没有可以撤销的替换", sc.window)
+		return
+	}
+
+	// 上方选择替换记录，默认为最近一次
+	journal := journals[0]
+	status := widget.NewLabel("")
+
+	var files *widget.List
+	var journalSelect *widget.Select
+	var revertAllBtn *widget.Button
+
+	// reload 恢复文件后重新读取替换记录：全部恢复的记录不再列出，尽量保持原来的选择
+	reload := func() {
+		journals, _ = sc.journals.List()
+		index := 0
+		for i, j := range journals {
+			if j.ID == journal.ID {
+				index = i
+			}
+		}
+		options := make([]string, len(journals))
+		for i, j := range journals {
+			options[i] = journalTitle(j)
+		}
+		journalSelect.Options = options
+		if len(journals) == 0 {
+			journal = &core.ReplaceJournal{}
+			journalSelect.ClearSelected()
+			journalSelect.Disable()
+			revertAllBtn.Disable()
+		} else {
+			journal = journals[index]
+			journalSelect.SetSelectedIndex(index)
+		}
+		files.Refresh()
+	}
+	revert := func(edits []core.FileEdit) {
+		message := sc.revertEdits(journal, edits)
+		reload()
+		status.SetText(message)
+	}
+
+	files = widget.NewList(
+		func() int {
+			return len(journal.Files)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, nil, widget.NewButton("恢复", nil), label)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(journal.Files) {
+				return
+			}
+			edit := journal.Files[id]
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(sc.quickOpenLabel(edit.Path))
+			row.Objects[1].(*widget.Button).OnTapped = func() {
+				revert([]core.FileEdit{edit})
+			}
+		},
+	)
+
+	journalSelect = widget.NewSelect(nil, func(string) {
+		if i := journalSelect.SelectedIndex(); i >= 0 && i < len(journals) {
+			journal = journals[i]
+		}
+		status.SetText("")
+		files.Refresh()
+	})
+
+	revertAllBtn = widget.NewButton("全部恢复", func() {
+		revert(append([]core.FileEdit(nil), journal.Files...))
+	})
+	reload()
+
+	content := container.NewBorder(journalSelect, container.NewBorder(nil, nil, nil, revertAllBtn, status), nil, nil, files)
+	d := dialog.NewCustom("撤销工作区替换", "关闭", content, sc.window)
+	size := sc.window.Canvas().Size()
+	d.Resize(fyne.NewSize(size.Width*0.6, size.Height*0.6))
+	d.Show()
+}
+
+// journalTitle 获取替换记录在列表中显示的标题
+func journalTitle(journal *core.ReplaceJournal) string {
+	return fmt.Sprintf("%s  %s（%d 个文件）", journal.CreatedAt.Format("2006-01-02 15:04"), journal.Description, len(journal.Files))
+}
+
+// revertEdits 把替换记录中的指定文件恢复为替换前的内容，刷新已打开的文档并从记录中去掉已恢复的文件
+// 返回恢复结果的说明
+func (sc *GuiController) revertEdits(journal *core.ReplaceJournal, edits []core.FileEdit) string {
+	reverted, err := core.RevertEdits(edits, sc.settings.KeepBackup)
+
+	// 已打开且没有未保存修改的文档直接显示恢复后的内容，其余的交给外部修改检测处理
+	for _, edit := range reverted {
+		doc := sc.findDocument(edit.Path)
+		if doc == nil || doc.state.HasUnsavedChanges() {
+			continue
+		}
+		row := min(doc.editor.CursorRow, strings.Count(edit.Before, "\n"))
+		doc.editor.replaceText(edit.Before, row, 0)
+		doc.state.SetOriginalContent(edit.Before)
+		doc.state.UpdateDiskSnapshot()
+		sc.updateTabTitle(doc)
+	}
+	sc.updateWindowTitle()
+
+	if removeErr := sc.journals.RemoveFiles(journal, reverted); removeErr != nil {
+		dialog.ShowError(removeErr, sc.window)
+	}
+	sc.updateRevertButton()
+
+	if sc.search != nil && sc.search.query.Text != "" {
+		sc.startWorkspaceSearch()
+	}
+
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("已恢复 %d 个文件，以下文件在替换之后被修改过或无法写入：\n%w", len(reverted), err), sc.window)
+	}
+	return fmt.Sprintf("已恢复 %d 个文件", len(reverted))
+}
+
+// updateRevertButton 有可以撤销的替换时启用撤销按钮
+func (sc *GuiController) updateRevertButton() {
+	p := sc.search
+	if p == nil || p.revertBtn == nil {
+		return
+	}
+	if sc.journals != nil {
+		if journal, _ := sc.journals.Latest(); journal != nil {
+			p.revertBtn.Enable()
+			return
+		}
+	}
+	p.revertBtn.Disable()
+}
@@ -0,0 +1,348 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"markup/internal/search"
+)
+
+// searchResultSeparator 搜索结果树中匹配节点ID的分隔符，节点ID为 文件路径 + 分隔符 + 序号
+const searchResultSeparator = "\x00"
+
+// searchPanel 左侧栏中的工作区搜索面板
+type searchPanel struct {
+	query       *widget.Entry  // 查找内容
+	replacement *widget.Entry  // 替换内容
+	include     *widget.Entry  // 包含的文件
+	exclude     *widget.Entry  // 排除的文件
+	regex       *widget.Check  // 正则表达式
+	matchCase   *widget.Check  // 区分大小写
+	wholeWord   *widget.Check  // 全词匹配
+	status      *widget.Label  // 搜索进度或结果数量
+	stopBtn     *widget.Button // 停止搜索
+	revertBtn   *widget.Button // 撤销工作区替换
+	tree        *widget.Tree   // 按文件分组的结果
+
+	queryText string                        // 当前结果对应的查找内容
+	matcher   *search.Matcher               // 当前结果对应的查找条件
+	results   []*search.FileResult          // 按找到的顺序排列的结果
+	byPath    map[string]*search.FileResult // 文件路径 -> 结果
+	matches   int                           // 已找到的匹配数
+
+	cancel context.CancelFunc // 取消正在进行的搜索，没有搜索时为 nil
+	seq    uint64             // 搜索序号，用于丢弃已取消的搜索的结果
+}
+
+// buildSearchPanel 构建工作区搜索面板
+func (sc *GuiController) buildSearchPanel() fyne.CanvasObject {
+	p := &searchPanel{byPath: make(map[string]*search.FileResult)}
+	sc.search = p
+
+	p.query = widget.NewEntry()
+	p.query.SetPlaceHolder("在工作区中查找")
+	p.query.OnSubmitted = func(string) {
+		sc.startWorkspaceSearch()
+	}
+	p.replacement = widget.NewEntry()
+	p.replacement.SetPlaceHolder("替换为")
+	p.replacement.OnSubmitted = func(string) {
+		sc.previewWorkspaceReplace()
+	}
+	replaceBtn := widget.NewButton("替换...", sc.previewWorkspaceReplace)
+	p.revertBtn = widget.NewButtonWithIcon("", theme.ContentUndoIcon(), sc.revertWorkspaceReplace)
+	sc.updateRevertButton()
+
+	p.include = widget.NewEntry()
+	p.include.SetPlaceHolder("包含的文件（如 docs/**, *.md）")
+	p.include.OnSubmitted = p.query.OnSubmitted
+	p.exclude = widget.NewEntry()
+	p.exclude.SetPlaceHolder("排除的文件（如 drafts, archive/**）")
+	p.exclude.OnSubmitted = p.query.OnSubmitted
+
+	p.regex = widget.NewCheck("正则", nil)
+	p.matchCase = widget.NewCheck("区分大小写", nil)
+	p.wholeWord = widget.NewCheck("全词匹配", nil)
+
+	p.status = widget.NewLabel("")
+	p.status.Wrapping = fyne.TextWrapWord
+	searchBtn := widget.NewButtonWithIcon("", theme.SearchIcon(), sc.startWorkspaceSearch)
+	p.stopBtn = widget.NewButtonWithIcon("", theme.MediaStopIcon(), sc.cancelWorkspaceSearch)
+	p.stopBtn.Disable()
+
+	p.tree = widget.NewTree(
+		// 子节点：根节点下为文件，文件下为匹配
+		func(uid widget.TreeNodeID) []widget.TreeNodeID {
+			if uid == "" {
+				ids := make([]widget.TreeNodeID, len(p.results))
+				for i, result := range p.results {
+					ids[i] = result.Path
+				}
+				return ids
+			}
+			result := p.byPath[uid]
+			if result == nil {
+				return nil
+			}
+			ids := make([]widget.TreeNodeID, len(result.Matches))
+			for i := range result.Matches {
+				ids[i] = uid + searchResultSeparator + strconv.Itoa(i)
+			}
+			return ids
+		},
+		// 是否为文件节点
+		func(uid widget.TreeNodeID) bool {
+			return uid == "" || !strings.Contains(uid, searchResultSeparator)
+		},
+		// 创建节点
+		func(branch bool) fyne.CanvasObject {
+			if branch {
+				label := widget.NewLabel("")
+				label.Truncation = fyne.TextTruncateEllipsis
+				return container.NewBorder(nil, nil, widget.NewIcon(theme.DocumentIcon()), nil, label)
+			}
+			text := widget.NewRichText()
+			text.Truncation = fyne.TextTruncateEllipsis
+			return text
+		},
+		// 更新节点
+		func(uid widget.TreeNodeID, branch bool, obj fyne.CanvasObject) {
+			if branch {
+				result := p.byPath[uid]
+				if result == nil {
+					return
+				}
+				label := obj.(*fyne.Container).Objects[0].(*widget.Label)
+				label.SetText(fmt.Sprintf("%s (%d)", result.RelPath, len(result.Matches)))
+				return
+			}
+			if match := p.lineMatch(uid); match != nil {
+				obj.(*widget.RichText).Segments = snippetSegments(match)
+				obj.(*widget.RichText).Refresh()
+			}
+		},
+	)
+	// 点击匹配时打开文件并选中匹配内容
+	p.tree.OnSelected = func(uid widget.TreeNodeID) {
+		defer p.tree.UnselectAll()
+		path, _, ok := strings.Cut(uid, searchResultSeparator)
+		if !ok {
+			return
+		}
+		if match := p.lineMatch(uid); match != nil {
+			sc.openSearchResult(path, match)
+		}
+	}
+
+	form := container.NewVBox(
+		container.NewBorder(nil, nil, nil, container.NewHBox(searchBtn, p.stopBtn), p.query),
+		container.NewBorder(nil, nil, nil, container.NewHBox(replaceBtn, p.revertBtn), p.replacement),
+		container.NewHBox(p.regex, p.matchCase, p.wholeWord),
+		p.include,
+		p.exclude,
+		p.status,
+	)
+	return container.NewBorder(form, nil, nil, nil, p.tree)
+}
+
+// lineMatch 获取搜索结果树中匹配节点对应的匹配
+func (p *searchPanel) lineMatch(uid widget.TreeNodeID) *search.LineMatch {
+	path, index, ok := strings.Cut(uid, searchResultSeparator)
+	if !ok {
+		return nil
+	}
+	result := p.byPath[path]
+	i, err := strconv.Atoi(index)
+	if result == nil || err != nil || i < 0 || i >= len(result.Matches) {
+		return nil
+	}
+	return &result.Matches[i]
+}
+
+// snippetSegments 把匹配所在行显示为行号加摘要，匹配内容加粗
+func snippetSegments(match *search.LineMatch) []widget.RichTextSegment {
+	plain := func(text string) *widget.TextSegment {
+		return &widget.TextSegment{Text: text, Style: widget.RichTextStyleInline}
+	}
+	bold := &widget.TextSegment{Text: match.Text(), Style: widget.RichTextStyleStrong}
+	bold.Style.Inline = true
+
+	return []widget.RichTextSegment{
+		plain(fmt.Sprintf("%d: ", match.Line)),
+		plain(strings.TrimLeft(match.Snippet[:match.Start], " \t")),
+		bold,
+		plain(match.Snippet[match.End:]),
+	}
+}
+
+// showWorkspaceSearch 切换到左侧栏的搜索面板，编辑器中选中了单行文本时用它作为查找内容
+func (sc *GuiController) showWorkspaceSearch() {
+	if sc.workspace == nil {
+		dialog.ShowInformation("工作区搜索", "请先打开文件夹", sc.window)
+		return
+	}
+	p := sc.search
+	if p == nil {
+		return
+	}
+
+	if sc.hasDocument() {
+		if selected := sc.editorEntry.SelectedText(); selected != "" && !strings.Contains(selected, "\n") {
+			p.query.SetText(selected)
+		}
+	}
+	sc.sidebar.Show()
+	sc.sidebarTabs.SelectIndex(1)
+	sc.window.Canvas().Focus(p.query)
+}
+
+// startWorkspaceSearch 取消正在进行的搜索，并在后台开始新的搜索
+func (sc *GuiController) startWorkspaceSearch() {
+	p := sc.search
+	if p == nil || sc.workspace == nil {
+		return
+	}
+	sc.cancelWorkspaceSearch()
+	sc.clearSearchResults()
+
+	matcher, err := search.Compile(p.query.Text, search.Options{
+		Regex:     p.regex.Checked,
+		MatchCase: p.matchCase.Checked,
+		WholeWord: p.wholeWord.Checked,
+	})
+	if errors.Is(err, search.ErrEmptyQuery) {
+		p.status.SetText("")
+		return
+	}
+	if err != nil {
+		p.status.SetText("正则表达式有误：" + err.Error())
+		return
+	}
+
+	// 有未保存修改的文档按编辑器中的内容搜索
+	contents := make(map[string]string)
+	for _, doc := range sc.documents {
+		if path := doc.state.GetCurrentFile(); path != "" && doc.state.HasUnsavedChanges() {
+			contents[path] = doc.state.GetCurrentContent()
+		}
+	}
+	req := &search.Request{
+		Root:     sc.workspace.GetRoot(),
+		Matcher:  matcher,
+		Include:  search.ParseGlobs(p.include.Text),
+		Exclude:  search.ParseGlobs(p.exclude.Text),
+		Contents: contents,
+	}
+
+	p.queryText = p.query.Text
+	p.matcher = matcher
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.seq++
+	seq := p.seq
+	p.status.SetText("正在搜索...")
+	p.stopBtn.Enable()
+
+	go func() {
+		err := search.SearchWorkspace(ctx, req, func(result *search.FileResult) {
+			fyne.Do(func() {
+				if p.seq == seq {
+					sc.addSearchResult(result)
+				}
+			})
+		})
+		cancel()
+		fyne.Do(func() {
+			if p.seq == seq {
+				sc.finishWorkspaceSearch(err)
+			}
+		})
+	}()
+}
+
+// cancelWorkspaceSearch 取消正在进行的搜索，已找到的结果保留
+func (sc *GuiController) cancelWorkspaceSearch() {
+	p := sc.search
+	if p == nil || p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.cancel = nil
+	p.seq++ // 丢弃尚未显示的结果
+	p.stopBtn.Disable()
+	p.status.SetText(fmt.Sprintf("已停止，%s", searchSummary(len(p.results), p.matches)))
+}
+
+// clearSearchResults 清空搜索结果
+func (sc *GuiController) clearSearchResults() {
+	p := sc.search
+	if p == nil {
+		return
+	}
+	p.queryText = ""
+	p.matcher = nil
+	p.results = nil
+	p.byPath = make(map[string]*search.FileResult)
+	p.matches = 0
+	p.tree.Refresh()
+}
+
+// addSearchResult 显示一个文件的搜索结果
+func (sc *GuiController) addSearchResult(result *search.FileResult) {
+	p := sc.search
+	p.results = append(p.results, result)
+	p.byPath[result.Path] = result
+	p.matches += len(result.Matches)
+	p.status.SetText(fmt.Sprintf("正在搜索...%s", searchSummary(len(p.results), p.matches)))
+	p.tree.Refresh()
+	p.tree.OpenBranch(result.Path)
+}
+
+// finishWorkspaceSearch 搜索结束后显示结果数量
+func (sc *GuiController) finishWorkspaceSearch(err error) {
+	p := sc.search
+	p.cancel = nil
+	p.stopBtn.Disable()
+
+	switch {
+	case errors.Is(err, search.ErrTooManyMatches):
+		p.status.SetText(fmt.Sprintf("匹配过多，只显示前 %d 处", search.MaxMatches))
+	case err != nil:
+		p.status.SetText("搜索失败：" + err.Error())
+	case p.matches == 0:
+		p.status.SetText("无结果")
+	default:
+		p.status.SetText(searchSummary(len(p.results), p.matches))
+	}
+}
+
+// searchSummary 获取结果数量的说明
+func searchSummary(files, matches int) string {
+	return fmt.Sprintf("%d 个文件中找到 %d 处", files, matches)
+}
+
+// openSearchResult 打开搜索结果所在的文件并选中匹配内容
+func (sc *GuiController) openSearchResult(path string, match *search.LineMatch) {
+	sc.loadFile(path)
+	if sc.appState.GetCurrentFile() != path {
+		return
+	}
+	sc.goToPosition(match.Line, match.Column)
+
+	// 搜索之后文件可能被修改过，只有内容一致时才选中
+	start := sc.editorEntry.cursorOffset()
+	end := start + len(match.Text())
+	if end <= len(sc.editorEntry.Text) && sc.editorEntry.Text[start:end] == match.Text() {
+		sc.editorEntry.selectRange(start, end)
+	}
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+
+	"markup/internal/core"
+)
+
+// 会话中保存的分屏名称
+const (
+	splitSidebar  = "sidebar"  // 文件树与其余部分
+	splitOutline  = "outline"  // 大纲与编辑区
+	splitEditor   = "editor"   // 编辑器与预览
+	splitProblems = "problems" // 编辑区与问题面板
+)
+
+// restoreSession 恢复上次退出时的窗口大小、显示模式、工作区和打开的文档
+// 已不存在的工作区和文件直接跳过
+func (sc *GuiController) restoreSession() {
+	session := sc.session
+
+	if session.WindowWidth > 0 && session.WindowHeight > 0 {
+		sc.window.Resize(fyne.NewSize(session.WindowWidth, session.WindowHeight))
+	}
+	if mode, ok := parseViewMode(session.ViewMode); ok {
+		sc.setViewMode(mode)
+	}
+	sc.darkTheme = session.Theme == "dark"
+	if session.Zoom > 0 {
+		sc.zoom = session.Zoom
+	}
+	sc.applyAppearance()
+
+	if session.Workspace != "" {
+		if info, err := os.Stat(session.Workspace); err == nil && info.IsDir() {
+			sc.loadWorkspace(session.Workspace)
+		}
+	}
+
+	var active *document
+	for _, saved := range session.Documents {
+		if saved.Path == "" || sc.findDocument(saved.Path) != nil {
+			continue
+		}
+		content, err := sc.appState.LoadFile(saved.Path)
+		if err != nil {
+			continue
+		}
+
+		doc := sc.newDocument(saved.Path, content, content)
+		sc.openDocument(doc)
+		restoreDocumentPosition(doc, saved)
+		if saved.Path == session.ActiveFile {
+			active = doc
+		}
+	}
+	if active != nil {
+		sc.selectDocument(active)
+	}
+}
+
+// restoreDocumentPosition 恢复文档的光标和滚动位置
+func restoreDocumentPosition(doc *document, saved core.SessionDocument) {
+	lines := strings.Split(doc.editor.Text, "\n")
+	row := min(max(saved.CursorRow, 0), len(lines)-1)
+	col := min(max(saved.CursorColumn, 0), len([]rune(lines[row])))
+
+	doc.editor.CursorRow = row
+	doc.editor.CursorColumn = col
+	doc.editor.Refresh()
+
+	// 标签页尚未布局时编辑器还没有大小，先按内容调整大小，否则滚动位置会被重置
+	doc.scroll.Content.Resize(doc.scroll.Content.MinSize().Max(doc.scroll.Size()))
+	doc.scroll.ScrollToOffset(fyne.NewPos(saved.ScrollX, saved.ScrollY))
+}
+
+// saveSession 保存当前的窗口大小、显示模式、工作区和打开的文档，未命名文档不保存
+func (sc *GuiController) saveSession() {
+	size := sc.window.Canvas().Size()
+	session := &core.Session{
+		ActiveFile:   sc.appState.GetCurrentFile(),
+		WindowWidth:  size.Width,
+		WindowHeight: size.Height,
+		Splits:       sc.session.Splits,
+		ViewMode:     sc.viewMode.key(),
+		Theme:        "light",
+		Zoom:         sc.zoom,
+	}
+	if sc.darkTheme {
+		session.Theme = "dark"
+	}
+	if sc.workspace != nil {
+		session.Workspace = sc.workspace.GetRoot()
+	}
+
+	if sc.editorSplit != nil {
+		session.Splits = map[string]float64{
+			splitSidebar:  sc.mainSplit.Offset,
+			splitOutline:  sc.contentSplit.Offset,
+			splitEditor:   sc.editorSplit.Offset,
+			splitProblems: sc.editorArea.Offset,
+		}
+	}
+
+	for _, doc := range sc.documents {
+		path := doc.state.GetCurrentFile()
+		if path == "" {
+			continue
+		}
+		session.Documents = append(session.Documents, core.SessionDocument{
+			Path:         path,
+			CursorRow:    doc.editor.CursorRow,
+			CursorColumn: doc.editor.CursorColumn,
+			ScrollX:      doc.scroll.Offset.X,
+			ScrollY:      doc.scroll.Offset.Y,
+		})
+	}
+
+	// 退出时无法再提示错误，保存失败只会导致下次启动不恢复会话
+	core.SaveSession(session)
+	sc.session = session
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// 默认快捷键，用户可以在设置文件的 keybindings 中修改
+var (
+	shortcutNewFile        = &desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutOpenFile       = &desktop.CustomShortcut{KeyName: fyne.KeyO, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutCommandPalette = &desktop.CustomShortcut{KeyName: fyne.KeyP, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	shortcutQuickOpen      = &desktop.CustomShortcut{KeyName: fyne.KeyT, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutOpenFolder     = &desktop.CustomShortcut{KeyName: fyne.KeyO, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	shortcutSave           = &desktop.CustomShortcut{KeyName: fyne.KeyS, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutSaveAs         = &desktop.CustomShortcut{KeyName: fyne.KeyS, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	shortcutExport         = &desktop.CustomShortcut{KeyName: fyne.KeyE, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	shortcutClose          = &desktop.CustomShortcut{KeyName: fyne.KeyW, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutQuit           = &desktop.CustomShortcut{KeyName: fyne.KeyQ, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutBold           = &desktop.CustomShortcut{KeyName: fyne.KeyB, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutItalic         = &desktop.CustomShortcut{KeyName: fyne.KeyI, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutFind           = &desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutReplace        = &desktop.CustomShortcut{KeyName: fyne.KeyH, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutFindNext       = &desktop.CustomShortcut{KeyName: fyne.KeyG, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutFindPrev       = &desktop.CustomShortcut{KeyName: fyne.KeyG, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	shortcutFindInFiles    = &desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	shortcutReplaceInFiles = &desktop.CustomShortcut{KeyName: fyne.KeyH, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	shortcutSplitMode      = &desktop.CustomShortcut{KeyName: fyne.KeyBackslash, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutEditMode       = &desktop.CustomShortcut{KeyName: fyne.KeyE, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutPreviewMode    = &desktop.CustomShortcut{KeyName: fyne.KeyP, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutZoomIn         = &desktop.CustomShortcut{KeyName: fyne.KeyEqual, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutZoomOut        = &desktop.CustomShortcut{KeyName: fyne.KeyMinus, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutZoomReset      = &desktop.CustomShortcut{KeyName: fyne.Key0, Modifier: fyne.KeyModifierShortcutDefault}
+	shortcutNextTab        = &desktop.CustomShortcut{KeyName: fyne.KeyTab, Modifier: fyne.KeyModifierControl}
+	shortcutPreviousTab    = &desktop.CustomShortcut{KeyName: fyne.KeyTab, Modifier: fyne.KeyModifierControl | fyne.KeyModifierShift}
+)
+
+// handleShortcut 执行绑定到快捷键的命令，返回是否已处理
+// 编辑器获得焦点时 Fyne 不会把快捷键传递给画布，由编辑器转发到这里
+func (sc *GuiController) handleShortcut(shortcut fyne.Shortcut) bool {
+	cmd := sc.commands.forShortcut(shortcut)
+	if cmd == nil {
+		return false
+	}
+	sc.executeCommand(cmd)
+	return true
+}
+
+// focusedInput 获取编辑器以外获得焦点的输入组件（如查找框），没有时返回 nil
+func (sc *GuiController) focusedInput() fyne.Shortcutable {
+	focused := sc.window.Canvas().Focused()
+	if _, ok := focused.(*markdownEditor); ok {
+		return nil
+	}
+	input, _ := focused.(fyne.Shortcutable)
+	return input
+}
+
+// undo 撤销：其他输入组件获得焦点时交给该组件，否则撤销编辑器中的修改
+func (sc *GuiController) undo() {
+	if input := sc.focusedInput(); input != nil {
+		input.TypedShortcut(&fyne.ShortcutUndo{})
+		return
+	}
+	sc.editorEntry.undo()
+}
+
+// redo 重做：其他输入组件获得焦点时交给该组件，否则重做编辑器中的修改
+func (sc *GuiController) redo() {
+	if input := sc.focusedInput(); input != nil {
+		input.TypedShortcut(&fyne.ShortcutRedo{})
+		return
+	}
+	sc.editorEntry.redo()
+}
+
+// registerShortcuts 在窗口画布上注册所有命令的快捷键
+func (sc *GuiController) registerShortcuts() {
+	for _, cmd := range sc.commands.commands {
+		if cmd.shortcut == nil {
+			continue
+		}
+		cmd := cmd
+		sc.window.Canvas().AddShortcut(cmd.shortcut, func(fyne.Shortcut) {
+			sc.executeCommand(cmd)
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+
+	"markup/internal/core"
+)
+
+// document 标签页中打开的一个文档，每个文档有自己的状态、编辑器（光标）和滚动位置
+type document struct {
+	state  *core.AppState     // 路径、内容、原始内容和大纲
+	editor *markdownEditor    // 编辑器
+	scroll *container.Scroll  // 编辑器滚动容器
+	tab    *container.TabItem // 对应的标签页
+
+	highlights *fyne.Container // 叠加在编辑器上方的查找结果高亮
+
+	draftID          string // 草稿ID，第一次写入草稿时分配
+	lastDraftContent string // 最近一次写入草稿的内容
+}
+
+// name 获取文档的显示名称
+func (d *document) name() string {
+	currentFile := d.state.GetCurrentFile()
+	if currentFile == "" {
+		return "未命名"
+	}
+	return filepath.Base(currentFile)
+}
+
+// tabTitle 获取标签页标题，有未保存的变更时在文件名后显示 *
+func (d *document) tabTitle() string {
+	if d.state.HasUnsavedChanges() {
+		return d.name() + " *"
+	}
+	return d.name()
+}
+
+// buildDocumentTabs 构建编辑器标签页容器
+func (sc *GuiController) buildDocumentTabs() fyne.CanvasObject {
+	sc.docTabs = container.NewDocTabs()
+	sc.docTabs.OnSelected = func(item *container.TabItem) {
+		if doc := sc.documentForTab(item); doc != nil {
+			sc.activateDocument(doc)
+		}
+	}
+	// 关闭标签页前检查未保存的更改
+	sc.docTabs.CloseIntercept = func(item *container.TabItem) {
+		if doc := sc.documentForTab(item); doc != nil {
+			sc.closeDocument(doc)
+		}
+	}
+	return sc.docTabs
+}
+
+// newDocument 创建新文档及其编辑器，尚未加入标签页
+func (sc *GuiController) newDocument(filePath, content, original string) *document {
+	state := core.NewAppState()
+	state.SetKeepBackup(sc.settings.KeepBackup)
+	state.SetCurrentFile(filePath)
+	state.SetCurrentContent(content)
+	state.SetOriginalContent(original)
+
+	editor := newMarkdownEditor()
+	editor.SetPlaceHolder("在此输入 Markdown 内容...")
+	editor.onShortcut = sc.handleShortcut
+
+	doc := &document{
+		state:      state,
+		editor:     editor,
+		highlights: container.NewWithoutLayout(),
+	}
+	doc.scroll = container.NewScroll(container.NewStack(editor, doc.highlights))
+	doc.tab = container.NewTabItem(doc.tabTitle(), doc.scroll)
+
+	// 设置文本变化事件，只有当前标签页需要刷新大纲、预览和检查结果
+	editor.OnChanged = func(content string) {
+		editor.recordChange()
+		state.SetCurrentContent(content)
+		if doc != sc.current {
+			sc.updateTabTitle(doc)
+			return
+		}
+		sc.updateWindowTitle()
+		sc.outlineDebouncer.trigger(sc.updateOutline)
+		sc.previewDebouncer.trigger(sc.updatePreview)
+		sc.lintDebouncer.trigger(sc.runLint)
+		if sc.find.visible() {
+			sc.findDebouncer.trigger(sc.updateFind)
+		}
+	}
+
+	// 光标移动时保持光标可见
+	editor.OnCursorChanged = func() {
+		if doc == sc.current {
+			sc.ensureCursorVisible()
+		}
+	}
+
+	// 编辑器滚动时同步预览
+	doc.scroll.OnScrolled = func(fyne.Position) {
+		if doc == sc.current {
+			sc.syncPreviewScroll()
+		}
+	}
+
+	return doc
+}
+
+// openDocument 在新标签页中打开文档并切换到该标签页
+func (sc *GuiController) openDocument(doc *document) {
+	// 首次进入编辑模式时构建编辑界面
+	if !sc.isEditing {
+		sc.isEditing = true
+		sc.window.SetContent(sc.BuildUI(sc.window))
+	}
+
+	// 记录磁盘状态，用于检测外部修改
+	doc.state.UpdateDiskSnapshot()
+	// 重新读取检查配置，使在外部修改的配置生效
+	sc.linters.reset()
+	doc.editor.setContent(doc.state.GetCurrentContent())
+
+	sc.documents = append(sc.documents, doc)
+	sc.docTabs.Append(doc.tab)
+	sc.selectDocument(doc)
+}
+
+// selectDocument 切换到指定文档的标签页
+func (sc *GuiController) selectDocument(doc *document) {
+	sc.docTabs.Select(doc.tab)
+	sc.activateDocument(doc)
+}
+
+// activateDocument 把文档设为当前文档，并刷新大纲、预览、检查结果和窗口标题
+func (sc *GuiController) activateDocument(doc *document) {
+	if sc.current == doc {
+		return
+	}
+	// 查找结果只在当前文档中高亮
+	if sc.current != nil {
+		sc.current.highlights.Objects = nil
+		sc.current.highlights.Refresh()
+	}
+	hadDocument := sc.hasDocument()
+	sc.current = doc
+	sc.appState = doc.state
+	sc.editorEntry = doc.editor
+	sc.editorScroll = doc.scroll
+
+	sc.hideChangeBanner()
+	sc.updateOutline()
+	sc.updatePreview()
+	sc.runLint()
+	sc.updateWindowTitle()
+	sc.selectFileInTree(doc.state.GetCurrentFile())
+	sc.resetFindScope()
+	// 打开第一个文档后启用需要文档的菜单项
+	if !hadDocument {
+		sc.refreshMainMenu()
+	}
+
+	if sc.viewMode != viewModePreview {
+		sc.window.Canvas().Focus(doc.editor)
+	}
+
+	// 切换后立即检查该文件是否在后台被修改
+	sc.checkExternalChange()
+}
+
+// documentForTab 查找标签页对应的文档
+func (sc *GuiController) documentForTab(item *container.TabItem) *document {
+	for _, doc := range sc.documents {
+		if doc.tab == item {
+			return doc
+		}
+	}
+	return nil
+}
+
+// findDocument 查找已打开指定文件的文档，未打开时返回 nil
+func (sc *GuiController) findDocument(filePath string) *document {
+	if filePath == "" {
+		return nil
+	}
+	for _, doc := range sc.documents {
+		if doc.state.GetCurrentFile() == filePath {
+			return doc
+		}
+	}
+	return nil
+}
+
+// closeDocument 关闭文档的标签页，有未保存的变更时先询问用户
+func (sc *GuiController) closeDocument(doc *document) {
+	sc.selectDocument(doc)
+	sc.confirmUnsavedChanges(func() {
+		sc.discardDraft(doc)
+
+		for i, existing := range sc.documents {
+			if existing == doc {
+				sc.documents = append(sc.documents[:i], sc.documents[i+1:]...)
+				break
+			}
+		}
+		sc.docTabs.Remove(doc.tab)
+
+		// 关闭最后一个标签页后，打开了工作区时保留编辑界面，否则回到启动界面
+		if len(sc.documents) == 0 {
+			if sc.workspace != nil {
+				sc.clearCurrentDocument()
+			} else {
+				sc.showStartupUI()
+			}
+			return
+		}
+
+		// 关闭当前标签页时 DocTabs 不一定会通知新的选中项
+		if next := sc.documentForTab(sc.docTabs.Selected()); next != nil {
+			sc.activateDocument(next)
+		}
+	})
+}
+
+// clearCurrentDocument 清空当前文档：编辑界面保留文件树，大纲、预览和问题面板都为空
+func (sc *GuiController) clearCurrentDocument() {
+	sc.current = nil
+	sc.appState = core.NewAppState()
+	sc.editorEntry = nil
+	sc.editorScroll = nil
+
+	sc.hideFind()
+	sc.hideChangeBanner()
+	sc.updateOutline()
+	sc.updatePreview()
+	sc.clearDiagnostics()
+	sc.updateWindowTitle()
+	sc.selectFileInTree("")
+	// 禁用需要文档的菜单项
+	sc.refreshMainMenu()
+}
+
+// closeCurrentDocument 关闭当前标签页
+func (sc *GuiController) closeCurrentDocument() {
+	if sc.current != nil {
+		sc.closeDocument(sc.current)
+	}
+}
+
+// cycleDocument 按标签页顺序切换文档，step 为 1 切换到下一个，-1 切换到上一个
+func (sc *GuiController) cycleDocument(step int) {
+	count := len(sc.documents)
+	if count < 2 || sc.current == nil {
+		return
+	}
+	for i, doc := range sc.documents {
+		if doc == sc.current {
+			sc.selectDocument(sc.documents[((i+step)%count+count)%count])
+			return
+		}
+	}
+}
+
+// confirmAllUnsavedChanges 依次询问所有有未保存变更的文档，全部处理完后执行 onProceed
+func (sc *GuiController) confirmAllUnsavedChanges(onProceed func()) {
+	sc.confirmUnsavedFrom(0, onProceed)
+}
+
+// confirmUnsavedFrom 从第 index 个文档开始依次询问未保存的变更
+func (sc *GuiController) confirmUnsavedFrom(index int, onProceed func()) {
+	for ; index < len(sc.documents); index++ {
+		doc := sc.documents[index]
+		if !doc.state.HasUnsavedChanges() {
+			continue
+		}
+
+		next := index + 1
+		sc.selectDocument(doc)
+		sc.confirmUnsavedChanges(func() {
+			sc.confirmUnsavedFrom(next, onProceed)
+		})
+		return
+	}
+	onProceed()
+}
+
+// updateTabTitle 更新文档的标签页标题
+func (sc *GuiController) updateTabTitle(doc *document) {
+	if doc == nil || sc.docTabs == nil {
+		return
+	}
+	if title := doc.tabTitle(); doc.tab.Text != title {
+		doc.tab.Text = title
+		sc.docTabs.Refresh()
+	}
+}
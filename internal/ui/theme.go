@@ -8,19 +8,40 @@ import (
 )
 
 // GitHubTheme 结构体，用于实现自定义主题
-type GitHubTheme struct{}
+type GitHubTheme struct {
+	dark  bool    // 是否使用深色配色
+	scale float32 // 文字缩放比例
+}
 
 // 断言 GitHubTheme 实现了 fyne.Theme 接口
 var _ fyne.Theme = (*GitHubTheme)(nil)
 
 // NewGitHubTheme 返回一个新的 GitHubTheme 实例
 func NewGitHubTheme() *GitHubTheme {
-	return &GitHubTheme{}
+	return &GitHubTheme{scale: 1}
+}
+
+// WithDark 返回使用深色或浅色配色的主题副本
+func (t *GitHubTheme) WithDark(dark bool) *GitHubTheme {
+	copied := *t
+	copied.dark = dark
+	return &copied
+}
+
+// WithScale 返回文字缩放为 scale 倍的主题副本
+func (t *GitHubTheme) WithScale(scale float32) *GitHubTheme {
+	copied := *t
+	copied.scale = scale
+	return &copied
 }
 
 // Color 返回指定名称和变体的颜色
 func (t *GitHubTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
-	// 忽略亮色/暗色变体，始终使用我们定义的颜色
+	// 忽略系统的亮色/暗色变体，由用户在视图菜单中选择配色
+	if t.dark {
+		return t.darkColor(name)
+	}
+
 	switch name {
 	case theme.ColorNameBackground:
 		return color.NRGBA{R: 0xe6, G: 0xea, B: 0xed, A: 0xff} // GitHub 更深的背景灰
@@ -70,6 +91,57 @@ func (t *GitHubTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant)
 	}
 }
 
+// darkColor 返回深色配色中指定名称的颜色
+func (t *GitHubTheme) darkColor(name fyne.ThemeColorName) color.Color {
+	switch name {
+	case theme.ColorNameBackground:
+		return color.NRGBA{R: 0x16, G: 0x1b, B: 0x22, A: 0xff} // GitHub 深色背景
+	case theme.ColorNameButton:
+		return color.NRGBA{R: 0x21, G: 0x26, B: 0x2d, A: 0xff}
+	case theme.ColorNameDisabled:
+		return color.NRGBA{R: 0x6e, G: 0x76, B: 0x81, A: 0xff}
+	case theme.ColorNameDisabledButton:
+		return color.NRGBA{R: 0x21, G: 0x26, B: 0x2d, A: 0x80}
+	case theme.ColorNameError:
+		return color.NRGBA{R: 0xf8, G: 0x51, B: 0x49, A: 0xff}
+	case theme.ColorNameFocus:
+		return color.NRGBA{R: 0x58, G: 0xa6, B: 0xff, A: 0xff} // 深色下的链接/焦点蓝
+	case theme.ColorNameForeground:
+		return color.NRGBA{R: 0xc9, G: 0xd1, B: 0xd9, A: 0xff} // 主要文本浅灰
+	case theme.ColorNameHover:
+		return color.NRGBA{R: 0x30, G: 0x36, B: 0x3d, A: 0xff}
+	case theme.ColorNameInputBackground:
+		return color.NRGBA{R: 0x0d, G: 0x11, B: 0x17, A: 0xff} // 输入框最深的背景
+	case theme.ColorNameInputBorder:
+		return color.NRGBA{R: 0x30, G: 0x36, B: 0x3d, A: 0xff}
+	case theme.ColorNamePlaceHolder:
+		return color.NRGBA{R: 0x8b, G: 0x94, B: 0x9e, A: 0xff}
+	case theme.ColorNamePressed:
+		return color.NRGBA{R: 0x48, G: 0x4f, B: 0x58, A: 0xff}
+	case theme.ColorNamePrimary:
+		return color.NRGBA{R: 0x1f, G: 0x6f, B: 0xeb, A: 0xff}
+	case theme.ColorNameScrollBar:
+		return color.NRGBA{R: 0x48, G: 0x4f, B: 0x58, A: 0xff}
+	case theme.ColorNameSelection:
+		return color.NRGBA{R: 0x38, G: 0x8b, B: 0xfd, A: 0x40}
+	case theme.ColorNameSeparator:
+		return color.NRGBA{R: 0x21, G: 0x26, B: 0x2d, A: 0xff}
+	case theme.ColorNameShadow:
+		return color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0x60}
+	case theme.ColorNameSuccess:
+		return color.NRGBA{R: 0x3f, G: 0xb9, B: 0x50, A: 0xff}
+	case theme.ColorNameWarning:
+		return color.NRGBA{R: 0xd2, G: 0x99, B: 0x22, A: 0xff}
+	case theme.ColorNameMenuBackground:
+		return color.NRGBA{R: 0x21, G: 0x26, B: 0x2d, A: 0xff}
+	case theme.ColorNameOverlayBackground:
+		return color.NRGBA{R: 0x21, G: 0x26, B: 0x2d, A: 0xff}
+	default:
+		// 对于未指定的颜色，回退到默认的暗色主题
+		return theme.DefaultTheme().Color(name, theme.VariantDark)
+	}
+}
+
 // Font 返回指定样式和大小的字体
 func (t *GitHubTheme) Font(style fyne.TextStyle) fyne.Resource {
 	// 暂不覆盖字体，使用Fyne的默认字体
@@ -82,8 +154,14 @@ func (t *GitHubTheme) Icon(name fyne.ThemeIconName) fyne.Resource {
 	return theme.DefaultTheme().Icon(name)
 }
 
-// Size 返回指定名称的大小
+// Size 返回指定名称的大小，文字相关的尺寸按缩放比例放大或缩小
 func (t *GitHubTheme) Size(name fyne.ThemeSizeName) float32 {
-	// 暂不覆盖尺寸，使用Fyne的默认尺寸
-	return theme.DefaultTheme().Size(name)
+	size := theme.DefaultTheme().Size(name)
+	switch name {
+	case theme.SizeNameText, theme.SizeNameHeadingText, theme.SizeNameSubHeadingText, theme.SizeNameCaptionText:
+		if t.scale > 0 {
+			size *= t.scale
+		}
+	}
+	return size
 }
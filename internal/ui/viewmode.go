@@ -0,0 +1,88 @@
+package ui
+
+// viewMode 编辑区的显示模式
+type viewMode int
+
+const (
+	viewModeSplit   viewMode = iota // 编辑与预览分屏
+	viewModeEdit                    // 仅编辑
+	viewModePreview                 // 仅预览
+)
+
+// viewModeNames 显示模式的界面名称，顺序与 viewMode 常量一致
+var viewModeNames = []string{"分屏", "编辑", "预览"}
+
+// viewModeKeys 显示模式保存到会话中的名称，顺序与 viewMode 常量一致
+var viewModeKeys = []string{"split", "edit", "preview"}
+
+// String 返回显示模式的界面名称
+func (m viewMode) String() string {
+	if int(m) < 0 || int(m) >= len(viewModeNames) {
+		return ""
+	}
+	return viewModeNames[m]
+}
+
+// key 返回显示模式保存到会话中的名称
+func (m viewMode) key() string {
+	if int(m) < 0 || int(m) >= len(viewModeKeys) {
+		return ""
+	}
+	return viewModeKeys[m]
+}
+
+// parseViewMode 解析会话中保存的显示模式名称
+func parseViewMode(key string) (viewMode, bool) {
+	for i, k := range viewModeKeys {
+		if k == key {
+			return viewMode(i), true
+		}
+	}
+	return viewModeSplit, false
+}
+
+// toggleViewMode 在指定模式和分屏模式之间切换
+func (sc *GuiController) toggleViewMode(mode viewMode) {
+	if sc.viewMode == mode {
+		mode = viewModeSplit
+	}
+	sc.setViewMode(mode)
+}
+
+// setViewMode 设置显示模式，只显示/隐藏已有组件而不重建界面
+func (sc *GuiController) setViewMode(mode viewMode) {
+	sc.viewMode = mode
+	sc.refreshMainMenu()
+	if !sc.isEditing || sc.editorSplit == nil {
+		return
+	}
+	sc.applyViewMode()
+
+	// 同步工具栏中的模式选择
+	if sc.viewModeSelect != nil && sc.viewModeSelect.Selected != mode.String() {
+		sc.viewModeSelect.SetSelected(mode.String())
+	}
+
+	// 编辑器可见时把焦点还给编辑器
+	if mode != viewModePreview && sc.hasDocument() {
+		sc.window.Canvas().Focus(sc.editorEntry)
+	}
+}
+
+// applyViewMode 按当前显示模式显示或隐藏编辑器和预览
+func (sc *GuiController) applyViewMode() {
+	switch sc.viewMode {
+	case viewModeEdit:
+		sc.docTabs.Show()
+		sc.previewScroll.Hide()
+	case viewModePreview:
+		sc.docTabs.Hide()
+		sc.previewScroll.Show()
+		sc.updatePreview()
+	default:
+		sc.docTabs.Show()
+		sc.previewScroll.Show()
+		sc.updatePreview()
+	}
+	sc.editorSplit.Refresh()
+}
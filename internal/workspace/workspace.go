@@ -0,0 +1,151 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// IsMarkdownFile 判断文件名是否为 Markdown 文件（.md 或 .markdown）
+func IsMarkdownFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".markdown")
+}
+
+// Workspace 工作区，对应一个本地文件夹
+type Workspace struct {
+	mutex    sync.RWMutex        // 读写锁
+	root     string              // 工作区根目录（绝对路径）
+	children map[string][]string // 目录路径 -> 直接子节点路径
+}
+
+// NewWorkspace 创建新的工作区实例
+func NewWorkspace(root string) (*Workspace, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Workspace{
+		root:     absRoot,
+		children: make(map[string][]string),
+	}
+	if err := w.Scan(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// GetRoot 获取工作区根目录
+func (w *Workspace) GetRoot() string {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.root
+}
+
+// Scan 重新遍历工作区目录，只保留 Markdown 文件及包含它们的目录
+func (w *Workspace) Scan() error {
+	w.mutex.RLock()
+	root := w.root
+	w.mutex.RUnlock()
+
+	children := make(map[string][]string)
+	if _, err := scanDir(root, children); err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.children = children
+	return nil
+}
+
+// scanDir 递归遍历目录，返回该目录下是否包含 Markdown 文件
+func scanDir(dir string, children map[string][]string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+
+	var dirs, files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		// 跳过隐藏文件和目录（如 .git）
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		if entry.IsDir() {
+			hasMarkdown, err := scanDir(path, children)
+			if err != nil {
+				// 无法读取的子目录直接忽略，不影响整个工作区
+				continue
+			}
+			if hasMarkdown {
+				dirs = append(dirs, path)
+			}
+		} else if IsMarkdownFile(name) {
+			files = append(files, path)
+		}
+	}
+
+	// 目录在前，文件在后，各自按名称排序
+	sort.Strings(dirs)
+	sort.Strings(files)
+	children[dir] = append(dirs, files...)
+
+	return len(dirs) > 0 || len(files) > 0, nil
+}
+
+// Children 获取目录下的直接子节点，dir 为空时返回根目录的子节点
+func (w *Workspace) Children(dir string) []string {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if dir == "" {
+		dir = w.root
+	}
+	// 返回副本以避免并发修改
+	nodes := make([]string, len(w.children[dir]))
+	copy(nodes, w.children[dir])
+	return nodes
+}
+
+// IsDir 判断节点是否为目录
+func (w *Workspace) IsDir(path string) bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if path == "" {
+		return true
+	}
+	_, ok := w.children[path]
+	return ok
+}
+
+// Files 获取工作区内所有 Markdown 文件路径（已排序）
+func (w *Workspace) Files() []string {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	var files []string
+	for _, nodes := range w.children {
+		for _, node := range nodes {
+			if _, isDir := w.children[node]; !isDir {
+				files = append(files, node)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// RelPath 获取相对于工作区根目录的路径
+func (w *Workspace) RelPath(path string) string {
+	rel, err := filepath.Rel(w.GetRoot(), path)
+	if err != nil {
+		return path
+	}
+	return rel
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"os"
+
+	"markup/internal/cli"
 	"markup/internal/ui"
 
 	"fyne.io/fyne/v2"
@@ -8,6 +11,15 @@ import (
 )
 
 func main() {
+	// 第一个参数为子命令时进入命令行模式，不创建窗口；否则作为要打开的文件或文件夹
+	var openPath string
+	if len(os.Args) > 1 {
+		if cli.IsCommand(os.Args[1]) {
+			os.Exit(cli.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+		}
+		openPath = os.Args[1]
+	}
+
 	// 创建应用实例，并提供一个唯一的ID来支持快捷键等功能
 	myApp := app.NewWithID("com.github.markedit")
 
@@ -25,10 +37,17 @@ func main() {
 	content := controller.BuildUI(myWindow)
 	myWindow.SetContent(content)
 
+	// 启动后恢复草稿并开始自动保存，然后打开命令行指定的路径
+	myApp.Lifecycle().SetOnStarted(func() {
+		controller.OnStarted()
+		if openPath != "" {
+			controller.OpenPath(openPath)
+		}
+	})
+
 	// 设置窗口关闭时的回调
 	myWindow.SetCloseIntercept(func() {
-		controller.OnWindowClose()
-		myApp.Quit()
+		controller.OnWindowClose(myApp.Quit)
 	})
 
 	// 显示窗口并运行应用